@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Ways final failures are reported to Telegram, set by --alert-mode
+const (
+	alertImmediate = "immediate" // One message per failure
+	alertBatched   = "batched"   // Periodic digests from failureDigest
+	alertOff       = "off"
+)
+
+const (
+	alertDigestInterval = 30 * time.Second // Longest a failure waits before its digest is sent
+	alertDigestSize     = 20               // Failures that send a digest early
+	alertDigestExamples = 3                // Failures quoted in each digest
+)
+
+// FailureDigest collects final failures per chat and sends them as one
+// summary every interval or every size failures, so a campaign with
+// thousands of failures doesn't flood the chat
+type FailureDigest struct {
+	interval time.Duration
+	size     int
+	send     func(message, chatID string, logger *Logger) bool
+	pending  map[string]*pendingDigest
+	mu       sync.Mutex
+}
+
+// pendingDigest holds one chat's failures since its last digest
+type pendingDigest struct {
+	count      int
+	categories map[FailureCategory]int
+	examples   []string
+	since      time.Time
+	timer      *time.Timer
+	logger     *Logger
+}
+
+// failureDigest batches the alerts of every campaign
+var failureDigest = NewFailureDigest(alertDigestInterval, alertDigestSize)
+
+// NewFailureDigest sends a digest interval after a chat's first pending
+// failure, or as soon as size failures are pending
+func NewFailureDigest(interval time.Duration, size int) *FailureDigest {
+	return &FailureDigest{
+		interval: interval,
+		size:     size,
+		send:     queueTelegramAlert,
+		pending:  make(map[string]*pendingDigest),
+	}
+}
+
+// Add records a final failure for chatID
+func (d *FailureDigest) Add(chatID, email, eventURL string, category FailureCategory, reason string, logger *Logger) {
+	if category == CategoryNone {
+		category = CategoryUnknown
+	}
+
+	d.mu.Lock()
+	p := d.pending[chatID]
+	if p == nil {
+		p = &pendingDigest{categories: make(map[FailureCategory]int), since: time.Now(), logger: logger}
+		p.timer = time.AfterFunc(d.interval, func() { d.flushBatch(chatID, p) })
+		d.pending[chatID] = p
+	}
+	p.count++
+	p.categories[category]++
+	if len(p.examples) < alertDigestExamples {
+		p.examples = append(p.examples, fmt.Sprintf("<code>%s</code> on %s: %s",
+			htmlEscape(email), htmlEscape(truncateString(lastPathSegment(eventURL), 20)), htmlEscape(reason)))
+	}
+	if p.count < d.size {
+		d.mu.Unlock()
+		return
+	}
+	// The batch is full: detach it so the next failure starts a new one. The
+	// send only queues it, so the worker doesn't wait on Telegram.
+	delete(d.pending, chatID)
+	p.timer.Stop()
+	d.mu.Unlock()
+	d.send(formatFailureDigest(p), chatID, p.logger)
+}
+
+// Flush sends chatID's pending failures now, if there are any
+func (d *FailureDigest) Flush(chatID string) {
+	d.mu.Lock()
+	p := d.pending[chatID]
+	d.mu.Unlock()
+	if p != nil {
+		d.flushBatch(chatID, p)
+	}
+}
+
+// flushBatch sends p if it is still chatID's pending batch. A timer that
+// fires after its batch was sent must not cut the next batch short.
+func (d *FailureDigest) flushBatch(chatID string, p *pendingDigest) {
+	d.mu.Lock()
+	if d.pending[chatID] != p {
+		d.mu.Unlock()
+		return
+	}
+	delete(d.pending, chatID)
+	d.mu.Unlock()
+
+	p.timer.Stop()
+	d.send(formatFailureDigest(p), chatID, p.logger)
+}
+
+// formatFailureDigest summarizes pending failures by category with a few examples
+func formatFailureDigest(p *pendingDigest) string {
+	var sb strings.Builder
+	noun := "registrations"
+	if p.count == 1 {
+		noun = "registration"
+	}
+	fmt.Fprintf(&sb, "❌ <b>%d %s failed</b> since %s\n", p.count, noun, p.since.Format("15:04:05"))
+	sb.WriteString("━━━━━━━━━━━━━━━━━━━━\n")
+	for _, category := range failureCategories {
+		if n := p.categories[category]; n > 0 {
+			fmt.Fprintf(&sb, "• %s: %d\n", category, n)
+		}
+	}
+	if len(p.examples) > 0 {
+		sb.WriteString("\n<b>Examples:</b>\n")
+		for _, example := range p.examples {
+			fmt.Fprintf(&sb, "• %s\n", example)
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// APIConfig describes how to submit a registration as a direct HTTP request
+type APIConfig struct {
+	Endpoint          string            `json:"endpoint"`
+	Method            string            `json:"method"`
+	ContentType       string            `json:"content_type"` // json or form
+	Fields            map[string]string `json:"fields"`
+	Headers           map[string]string `json:"headers"`
+	SuccessStatus     []int             `json:"success_status"`
+	SuccessContains   string            `json:"success_contains"`
+	FallbackToBrowser bool              `json:"fallback_to_browser"`
+}
+
+// loadAPIConfig reads and validates an API mode configuration file
+func loadAPIConfig(filename string) (*APIConfig, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("API config file not found: %s", filename)
+	}
+
+	var cfg APIConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid API config %s: %v", filename, err)
+	}
+
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("API config %s: endpoint is required", filename)
+	}
+	if len(cfg.Fields) == 0 {
+		return nil, fmt.Errorf("API config %s: at least one field mapping is required", filename)
+	}
+	if cfg.Method == "" {
+		cfg.Method = http.MethodPost
+	}
+	cfg.Method = strings.ToUpper(cfg.Method)
+	if cfg.ContentType == "" {
+		cfg.ContentType = "json"
+	}
+	if cfg.ContentType != "json" && cfg.ContentType != "form" {
+		return nil, fmt.Errorf("API config %s: content_type must be json or form", filename)
+	}
+	if len(cfg.SuccessStatus) == 0 {
+		cfg.SuccessStatus = []int{http.StatusOK, http.StatusCreated}
+	}
+
+	return &cfg, nil
+}
+
+// expandAPIPlaceholders fills {first_name}-style placeholders with registration values
+func expandAPIPlaceholders(template, eventURL, firstName, lastName, email, organization string) string {
+	return strings.NewReplacer(
+		"{first_name}", firstName,
+		"{last_name}", lastName,
+		"{email}", email,
+		"{organization}", organization,
+		"{event_url}", eventURL,
+		"{event_id}", lastPathSegment(eventURL),
+	).Replace(template)
+}
+
+// newProxyHTTPClient builds an HTTP client routed through proxy (direct when nil)
+func newProxyHTTPClient(proxy *ProxyConfig, timeout time.Duration) (*http.Client, error) {
+	transport := &http.Transport{}
+	if proxy != nil {
+		proxyURL, err := url.Parse(proxy.Server)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy server %s: %v", proxy.Server, err)
+		}
+		if proxy.Username != "" {
+			proxyURL.User = url.UserPassword(proxy.Username, proxy.Password)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
+
+// registerViaAPI submits a registration directly over HTTP. needsBrowser is true
+// when the response suggests the form requires JavaScript and browser mode should be used.
+func registerViaAPI(ctx context.Context, cfg *APIConfig, proxy *ProxyConfig, eventURL, firstName, lastName, email, organization string, logger *Logger) (success bool, message string, needsBrowser bool) {
+	endpoint := expandAPIPlaceholders(cfg.Endpoint, eventURL, firstName, lastName, email, organization)
+
+	var body io.Reader
+	contentType := "application/json"
+	if cfg.ContentType == "form" {
+		values := url.Values{}
+		for key, value := range cfg.Fields {
+			values.Set(key, expandAPIPlaceholders(value, eventURL, firstName, lastName, email, organization))
+		}
+		body = strings.NewReader(values.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	} else {
+		fields := make(map[string]string, len(cfg.Fields))
+		for key, value := range cfg.Fields {
+			fields[key] = expandAPIPlaceholders(value, eventURL, firstName, lastName, email, organization)
+		}
+		jsonData, err := json.Marshal(fields)
+		if err != nil {
+			return false, fmt.Sprintf("Failed to encode API payload: %v", err), false
+		}
+		body = strings.NewReader(string(jsonData))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, cfg.Method, endpoint, body)
+	if err != nil {
+		return false, fmt.Sprintf("Invalid API request: %v", err), false
+	}
+	req.Header.Set("Content-Type", contentType)
+	for key, value := range config.ExtraHeaders {
+		req.Header.Set(key, value)
+	}
+	for key, value := range cfg.Headers {
+		req.Header.Set(key, expandAPIPlaceholders(value, eventURL, firstName, lastName, email, organization))
+	}
+
+	client, err := newProxyHTTPClient(proxy, config.PageLoadWait)
+	if err != nil {
+		return false, err.Error(), false
+	}
+
+	logger.Debug("📤 API %s %s", cfg.Method, endpoint)
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Sprintf("API request failed: %v", err), false
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	text := string(respBody)
+
+	statusOK := false
+	for _, code := range cfg.SuccessStatus {
+		if resp.StatusCode == code {
+			statusOK = true
+			break
+		}
+	}
+
+	if statusOK && (cfg.SuccessContains == "" || strings.Contains(strings.ToLower(text), strings.ToLower(cfg.SuccessContains))) {
+		return true, successMessage(detectedByAPI, fmt.Sprintf("HTTP %d", resp.StatusCode)), false
+	}
+
+	if keyword := matchAlreadyRegistered(text); keyword != "" {
+		return false, fmt.Sprintf("%s (found: %s)", alreadyRegisteredMessage, keyword), false
+	}
+
+	// HTML pages and method/verification rejections usually mean the form needs a real browser
+	isHTML := strings.Contains(resp.Header.Get("Content-Type"), "text/html")
+	switch resp.StatusCode {
+	case http.StatusForbidden, http.StatusMethodNotAllowed, http.StatusUnsupportedMediaType:
+		needsBrowser = true
+	default:
+		needsBrowser = isHTML
+	}
+
+	return false, fmt.Sprintf("API error (HTTP %d): %s", resp.StatusCode, truncateString(strings.TrimSpace(text), 200)), needsBrowser
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// blockableResources are the Playwright resource types --block-resources
+// accepts. The document itself can't be blocked or there'd be no form.
+var blockableResources = []string{
+	"image", "media", "font", "stylesheet", "script", "texttrack",
+	"xhr", "fetch", "eventsource", "websocket", "manifest", "other",
+}
+
+// parseResourceTypes splits a comma-separated --block-resources value and
+// checks each type
+func parseResourceTypes(value string) ([]string, error) {
+	var types []string
+	for _, t := range splitList(strings.ToLower(value)) {
+		known := false
+		for _, b := range blockableResources {
+			if t == b {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown resource type %q (expected some of %s)", t, strings.Join(blockableResources, ", "))
+		}
+		types = append(types, t)
+	}
+	return types, nil
+}
+
+// blockResources aborts every request in browserContext whose resource type
+// is in types, so pages skip images, fonts and the like
+func blockResources(browserContext playwright.BrowserContext, types []string) error {
+	blocked := make(map[string]bool, len(types))
+	for _, t := range types {
+		blocked[t] = true
+	}
+	return browserContext.Route("**/*", func(route playwright.Route) {
+		if blocked[route.Request().ResourceType()] {
+			route.Abort("blockedbyclient")
+			return
+		}
+		route.Continue()
+	})
+}
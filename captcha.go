@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// captchaBlockedMessage prefixes failures where a captcha stopped the registration
+const captchaBlockedMessage = "Captcha blocked registration"
+
+// CaptchaChallenge describes a captcha found on a registration page
+type CaptchaChallenge struct {
+	Kind    string // hcaptcha or recaptcha
+	SiteKey string
+	PageURL string
+}
+
+// CaptchaSolver solves a challenge and returns the response token to inject.
+// Implementations are selected with --captcha-provider.
+type CaptchaSolver interface {
+	Solve(ctx context.Context, challenge CaptchaChallenge) (string, error)
+}
+
+// newCaptchaSolver creates the solver for provider using apiKey
+func newCaptchaSolver(provider, apiKey string) (CaptchaSolver, error) {
+	switch strings.ToLower(provider) {
+	case "2captcha":
+		return &TwoCaptchaSolver{
+			apiKey:       apiKey,
+			baseURL:      "https://2captcha.com",
+			pollInterval: 5 * time.Second,
+			timeout:      3 * time.Minute,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown captcha provider %q (expected 2captcha)", provider)
+	}
+}
+
+// captchaMarkers are the selectors that reveal a captcha, in detection order
+var captchaMarkers = []struct {
+	kind     string
+	selector string
+}{
+	{"hcaptcha", "iframe[src*='hcaptcha']"},
+	{"hcaptcha", ".h-captcha"},
+	{"recaptcha", "iframe[src*='recaptcha']"},
+	{"recaptcha", ".g-recaptcha"},
+	{"recaptcha", "[data-sitekey]"},
+}
+
+// detectCaptcha returns the captcha on page, or nil if there is none
+func detectCaptcha(page playwright.Page) *CaptchaChallenge {
+	for _, marker := range captchaMarkers {
+		locator := page.Locator(marker.selector).First()
+		if count, err := locator.Count(); err != nil || count == 0 {
+			continue
+		}
+
+		challenge := &CaptchaChallenge{Kind: marker.kind, PageURL: page.URL()}
+		if siteKey, err := page.Locator("[data-sitekey]").First().GetAttribute("data-sitekey", playwright.LocatorGetAttributeOptions{
+			Timeout: milliseconds(probeTimeout),
+		}); err == nil && siteKey != "" {
+			challenge.SiteKey = siteKey
+		} else if src, err := locator.GetAttribute("src", playwright.LocatorGetAttributeOptions{
+			Timeout: milliseconds(probeTimeout),
+		}); err == nil {
+			challenge.SiteKey = siteKeyFromFrameURL(src)
+		}
+		return challenge
+	}
+	return nil
+}
+
+// siteKeyFromFrameURL extracts the sitekey from a captcha iframe URL. reCAPTCHA
+// passes it as the k query parameter, hCaptcha as sitekey in the fragment.
+func siteKeyFromFrameURL(src string) string {
+	u, err := url.Parse(src)
+	if err != nil {
+		return ""
+	}
+	if key := u.Query().Get("k"); key != "" {
+		return key
+	}
+	if key := u.Query().Get("sitekey"); key != "" {
+		return key
+	}
+	fragment, _ := url.ParseQuery(u.Fragment)
+	return fragment.Get("sitekey")
+}
+
+// injectCaptchaToken fills the captcha response fields with token and fires
+// the widget's data-callback if the page registered one
+func injectCaptchaToken(page playwright.Page, token string) error {
+	_, err := page.Evaluate(`(token) => {
+		document.querySelectorAll("textarea[name='g-recaptcha-response'], textarea[name='h-captcha-response'], #g-recaptcha-response").forEach(el => {
+			el.value = token;
+			el.innerHTML = token;
+		});
+		const widget = document.querySelector("[data-callback]");
+		if (widget && typeof window[widget.dataset.callback] === "function") {
+			window[widget.dataset.callback](token);
+		}
+	}`, token)
+	return err
+}
+
+// handleCaptcha checks page for a captcha and solves it when a solver is configured.
+// It returns a non-empty failure message when the captcha blocks registration.
+func handleCaptcha(ctx context.Context, page playwright.Page, logger *Logger) string {
+	challenge := detectCaptcha(page)
+	if challenge == nil {
+		return ""
+	}
+	logger.Warning("🧩 %s detected on %s", challenge.Kind, challenge.PageURL)
+
+	if config.Captcha == nil {
+		return fmt.Sprintf("%s: %s (no --captcha-api-key)", captchaBlockedMessage, challenge.Kind)
+	}
+	if challenge.SiteKey == "" {
+		return fmt.Sprintf("%s: %s sitekey not found", captchaBlockedMessage, challenge.Kind)
+	}
+
+	logger.Info("🧩 Solving %s...", challenge.Kind)
+	token, err := config.Captcha.Solve(ctx, *challenge)
+	if err != nil {
+		return fmt.Sprintf("%s: %s solve failed: %v", captchaBlockedMessage, challenge.Kind, err)
+	}
+	if err := injectCaptchaToken(page, token); err != nil {
+		return fmt.Sprintf("%s: failed to inject token: %v", captchaBlockedMessage, err)
+	}
+	logger.Info("🧩 Captcha solved")
+	return ""
+}
+
+// TwoCaptchaSolver solves captchas through the 2captcha.com API
+type TwoCaptchaSolver struct {
+	apiKey       string
+	baseURL      string
+	pollInterval time.Duration
+	timeout      time.Duration
+}
+
+// twoCaptchaResponse is the JSON reply of the in.php and res.php endpoints
+type twoCaptchaResponse struct {
+	Status  int    `json:"status"`
+	Request string `json:"request"`
+}
+
+// Solve submits the challenge and polls until a token is ready
+func (s *TwoCaptchaSolver) Solve(ctx context.Context, challenge CaptchaChallenge) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	params := url.Values{
+		"key":     {s.apiKey},
+		"pageurl": {challenge.PageURL},
+		"json":    {"1"},
+	}
+	switch challenge.Kind {
+	case "hcaptcha":
+		params.Set("method", "hcaptcha")
+		params.Set("sitekey", challenge.SiteKey)
+	default:
+		params.Set("method", "userrecaptcha")
+		params.Set("googlekey", challenge.SiteKey)
+	}
+
+	submitted, err := s.call(ctx, "/in.php", params)
+	if err != nil {
+		return "", err
+	}
+	if submitted.Status != 1 {
+		return "", fmt.Errorf("2captcha rejected the task: %s", submitted.Request)
+	}
+
+	poll := url.Values{
+		"key":    {s.apiKey},
+		"action": {"get"},
+		"id":     {submitted.Request},
+		"json":   {"1"},
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("2captcha: %v", ctx.Err())
+		case <-time.After(s.pollInterval):
+		}
+
+		result, err := s.call(ctx, "/res.php", poll)
+		if err != nil {
+			return "", err
+		}
+		if result.Status == 1 {
+			return result.Request, nil
+		}
+		if result.Request != "CAPCHA_NOT_READY" {
+			return "", fmt.Errorf("2captcha: %s", result.Request)
+		}
+	}
+}
+
+// call sends params to a 2captcha endpoint and decodes the reply
+func (s *TwoCaptchaSolver) call(ctx context.Context, path string, params url.Values) (*twoCaptchaResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("2captcha request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var result twoCaptchaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid 2captcha response (HTTP %d): %v", resp.StatusCode, err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Environment variables for bot access control, used when the matching flag
+// and config file entry are unset
+const (
+	allowedChatsEnv = "ALLOWED_CHAT_IDS"
+	adminChatEnv    = "ADMIN_CHAT_ID"
+)
+
+// ChatAllowlist decides which chats the bot answers. The admin chat is
+// always allowed and can change the list at runtime with /allow and /deny.
+// With no admin and no allowed chats the bot is open to everyone.
+type ChatAllowlist struct {
+	admin int64
+	chats map[int64]bool
+	mu    sync.Mutex
+}
+
+// NewChatAllowlist allows admin (0 for none) and chats
+func NewChatAllowlist(admin int64, chats []int64) *ChatAllowlist {
+	a := &ChatAllowlist{admin: admin, chats: make(map[int64]bool)}
+	for _, id := range chats {
+		a.chats[id] = true
+	}
+	return a
+}
+
+// Open reports whether the bot answers every chat
+func (a *ChatAllowlist) Open() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.admin == 0 && len(a.chats) == 0
+}
+
+// Allowed reports whether chatID may use the bot
+func (a *ChatAllowlist) Allowed(chatID int64) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return (a.admin == 0 && len(a.chats) == 0) || chatID == a.admin || a.chats[chatID]
+}
+
+// IsAdmin reports whether chatID may change the allowlist
+func (a *ChatAllowlist) IsAdmin(chatID int64) bool {
+	return a.admin != 0 && chatID == a.admin
+}
+
+// Allow adds chatID, reporting false if it was already allowed
+func (a *ChatAllowlist) Allow(chatID int64) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.chats[chatID] {
+		return false
+	}
+	a.chats[chatID] = true
+	return true
+}
+
+// Deny removes chatID, reporting false if it wasn't allowed
+func (a *ChatAllowlist) Deny(chatID int64) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.chats[chatID] {
+		return false
+	}
+	delete(a.chats, chatID)
+	return true
+}
+
+// Chats lists the allowed chats other than the admin, in order
+func (a *ChatAllowlist) Chats() []int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	ids := make([]int64, 0, len(a.chats))
+	for id := range a.chats {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// resolveChatAccess sets the bot's allowed chats and admin from the flags,
+// then ALLOWED_CHAT_IDS and ADMIN_CHAT_ID, then the config file
+func resolveChatAccess(flagChats, flagAdmin string) error {
+	chats := flagChats
+	if chats == "" {
+		chats = os.Getenv(allowedChatsEnv)
+	}
+	if chats != "" {
+		ids, err := parseChatIDs(chats)
+		if err != nil {
+			return err
+		}
+		config.AllowedChats = ids
+	}
+
+	admin := flagAdmin
+	if admin == "" {
+		admin = strings.TrimSpace(os.Getenv(adminChatEnv))
+	}
+	if admin != "" {
+		ids, err := parseChatIDs(admin)
+		if err != nil || len(ids) != 1 {
+			return fmt.Errorf("invalid admin chat ID %q", admin)
+		}
+		config.AdminChat = ids[0]
+	}
+	return nil
+}
+
+// parseChatIDs reads a comma-separated list of chat IDs
+func parseChatIDs(value string) ([]int64, error) {
+	var ids []int64
+	for _, field := range splitList(value) {
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil || id == 0 {
+			return nil, fmt.Errorf("invalid chat ID %q", field)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CampaignConfig holds the campaign parameters that can come from a --config file
+type CampaignConfig struct {
+	FirstName      string
+	LastName       string
+	Organization   string
+	EmailsFile     string
+	EventsFile     string
+	ProxiesFile    string
+	TelegramChatID string
+	Profile        string
+}
+
+// configFile is the on-disk form of --config. Durations are strings like "30s".
+type configFile struct {
+	TelegramToken     string            `json:"telegram_token" yaml:"telegram_token"`
+	TelegramChatID    string            `json:"telegram_chat_id" yaml:"telegram_chat_id"`
+	FirstName         string            `json:"first_name" yaml:"first_name"`
+	LastName          string            `json:"last_name" yaml:"last_name"`
+	Organization      string            `json:"organization" yaml:"organization"`
+	Emails            string            `json:"emails" yaml:"emails"`
+	Events            string            `json:"events" yaml:"events"`
+	Proxies           string            `json:"proxies" yaml:"proxies"`
+	Profile           string            `json:"profile" yaml:"profile"`
+	Workers           int               `json:"workers" yaml:"workers"`
+	Retries           int               `json:"retries" yaml:"retries"`
+	RetryBaseDelay    string            `json:"retry_base_delay" yaml:"retry_base_delay"`
+	RetryMaxDelay     string            `json:"retry_max_delay" yaml:"retry_max_delay"`
+	PageLoadWait      string            `json:"page_load_wait" yaml:"page_load_wait"`
+	ElementWait       string            `json:"element_wait" yaml:"element_wait"`
+	FieldDelay        string            `json:"field_delay" yaml:"field_delay"`
+	SubmitWait        string            `json:"submit_wait" yaml:"submit_wait"`
+	StartJitter       string            `json:"start_jitter" yaml:"start_jitter"`
+	RampDelay         string            `json:"ramp_delay" yaml:"ramp_delay"`
+	NavRetries        *int              `json:"nav_retries" yaml:"nav_retries"`
+	ProxySessionScope string            `json:"proxy_session_scope" yaml:"proxy_session_scope"`
+	PinProxy          bool              `json:"pin_proxy" yaml:"pin_proxy"`
+	ExtraHeaders      map[string]string `json:"extra_headers" yaml:"extra_headers"`
+	FailFast          bool              `json:"fail_fast" yaml:"fail_fast"`
+	FailFastOn        []string          `json:"fail_fast_on" yaml:"fail_fast_on"`
+	BlockResources    []string          `json:"block_resources" yaml:"block_resources"`
+	Webhook           string            `json:"webhook" yaml:"webhook"`
+	OutputFormat      string            `json:"output_format" yaml:"output_format"`
+	GroupBy           string            `json:"group_by" yaml:"group_by"`
+	Browser           string            `json:"browser" yaml:"browser"`
+	AllowedChatIDs    []int64           `json:"allowed_chat_ids" yaml:"allowed_chat_ids"`
+	AdminChatID       int64             `json:"admin_chat_id" yaml:"admin_chat_id"`
+}
+
+// LoadConfigFile reads a JSON or YAML (.yml/.yaml) config file on top of the
+// current defaults and validates it
+func LoadConfigFile(path string) (Config, error) {
+	cfg := config
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, fmt.Errorf("config file not found: %s", path)
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config file %s: %v", path, err)
+	}
+
+	var file configFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		// An empty file sets nothing, as it does for yaml.Unmarshal
+		if err = decoder.Decode(&file); errors.Is(err, io.EOF) {
+			err = nil
+		}
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&file)
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+
+	var problems []string
+	duration := func(name, value string, target *time.Duration) {
+		if value == "" {
+			return
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			problems = append(problems, fmt.Sprintf("%s: invalid duration %q", name, value))
+			return
+		}
+		*target = d
+	}
+
+	if file.TelegramToken != "" {
+		if !strings.Contains(file.TelegramToken, ":") {
+			problems = append(problems, "telegram_token: expected <bot id>:<secret>")
+		}
+		cfg.TelegramToken = file.TelegramToken
+	}
+	if file.Workers != 0 {
+		if file.Workers < 1 {
+			problems = append(problems, "workers: must be at least 1")
+		}
+		cfg.MaxWorkers = file.Workers
+	}
+	if file.Retries != 0 {
+		if file.Retries < minRegistrationRetries || file.Retries > maxRegistrationRetries {
+			problems = append(problems, fmt.Sprintf("retries: must be %d-%d", minRegistrationRetries, maxRegistrationRetries))
+		}
+		cfg.RegistrationRetry = file.Retries
+	}
+	duration("retry_base_delay", file.RetryBaseDelay, &cfg.RetryBaseDelay)
+	duration("retry_max_delay", file.RetryMaxDelay, &cfg.RetryMaxDelay)
+	duration("page_load_wait", file.PageLoadWait, &cfg.PageLoadWait)
+	duration("element_wait", file.ElementWait, &cfg.ElementWait)
+	duration("field_delay", file.FieldDelay, &cfg.FieldDelay)
+	duration("submit_wait", file.SubmitWait, &cfg.SubmitWait)
+	duration("start_jitter", file.StartJitter, &cfg.StartJitter)
+	duration("ramp_delay", file.RampDelay, &cfg.RampDelay)
+	if file.NavRetries != nil {
+		if *file.NavRetries < 0 {
+			problems = append(problems, "nav_retries: must be 0 or more")
+		}
+		cfg.NavRetries = *file.NavRetries
+	}
+	if file.ProxySessionScope != "" {
+		if file.ProxySessionScope != "task" && file.ProxySessionScope != "worker" {
+			problems = append(problems, "proxy_session_scope: expected task or worker")
+		}
+		cfg.ProxySessionScope = file.ProxySessionScope
+	}
+	cfg.PinProxy = cfg.PinProxy || file.PinProxy
+	cfg.FailFast = cfg.FailFast || file.FailFast
+	if len(file.FailFastOn) > 0 {
+		cfg.FailFastStatuses = file.FailFastOn
+	}
+	if len(file.BlockResources) > 0 {
+		types, err := parseResourceTypes(strings.Join(file.BlockResources, ","))
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("block_resources: %v", err))
+		}
+		cfg.BlockResources = types
+	}
+	if file.Webhook != "" {
+		cfg.WebhookURL = file.Webhook
+	}
+	if file.OutputFormat != "" {
+		switch file.OutputFormat {
+		case "json", "csv", "both":
+		default:
+			problems = append(problems, "output_format: expected json, csv or both")
+		}
+		cfg.OutputFormat = file.OutputFormat
+	}
+	if file.Browser != "" {
+		if !validBrowserEngine(file.Browser) {
+			problems = append(problems, fmt.Sprintf("browser: expected %s", strings.Join(browserEngines, ", ")))
+		}
+		cfg.Browser = file.Browser
+	}
+	if len(file.AllowedChatIDs) > 0 {
+		cfg.AllowedChats = file.AllowedChatIDs
+	}
+	if file.AdminChatID != 0 {
+		cfg.AdminChat = file.AdminChatID
+	}
+	if file.GroupBy != "" {
+		if file.GroupBy != "email" {
+			problems = append(problems, "group_by: expected email")
+		}
+		cfg.GroupBy = file.GroupBy
+	}
+
+	// Headers already given on the command line take precedence
+	headers := make(map[string]string, len(cfg.ExtraHeaders)+len(file.ExtraHeaders))
+	for key, value := range file.ExtraHeaders {
+		headers[key] = value
+	}
+	for key, value := range cfg.ExtraHeaders {
+		headers[key] = value
+	}
+	cfg.ExtraHeaders = headers
+
+	// The registration identity is all-or-nothing so a file can't half-configure it
+	identity := map[string]string{"first_name": file.FirstName, "last_name": file.LastName, "organization": file.Organization}
+	var missing []string
+	for _, key := range []string{"first_name", "last_name", "organization"} {
+		if identity[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 && len(missing) < len(identity) {
+		problems = append(problems, fmt.Sprintf("missing required field(s): %s", strings.Join(missing, ", ")))
+	}
+
+	cfg.Campaign = CampaignConfig{
+		FirstName:      file.FirstName,
+		LastName:       file.LastName,
+		Organization:   file.Organization,
+		EmailsFile:     file.Emails,
+		EventsFile:     file.Events,
+		ProxiesFile:    file.Proxies,
+		TelegramChatID: file.TelegramChatID,
+		Profile:        file.Profile,
+	}
+
+	if len(problems) > 0 {
+		return cfg, fmt.Errorf("invalid config file %s:\n  %s", path, strings.Join(problems, "\n  "))
+	}
+	return cfg, nil
+}
+
+// applyConfigFile makes cfg the active configuration while letting flags given
+// explicitly on the command line override the file's values
+func applyConfigFile(cfg Config) {
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	setFlag := func(name, value string) {
+		if value != "" && !explicit[name] {
+			flag.Set(name, value)
+		}
+	}
+
+	// Keep the map the --extra-headers flag writes into
+	for key, value := range cfg.ExtraHeaders {
+		config.ExtraHeaders[key] = value
+	}
+	cfg.ExtraHeaders = config.ExtraHeaders
+	config = cfg
+
+	setFlag("first-name", cfg.Campaign.FirstName)
+	setFlag("last-name", cfg.Campaign.LastName)
+	setFlag("organization", cfg.Campaign.Organization)
+	setFlag("emails", cfg.Campaign.EmailsFile)
+	setFlag("events", cfg.Campaign.EventsFile)
+	setFlag("proxies", cfg.Campaign.ProxiesFile)
+	setFlag("telegram", cfg.Campaign.TelegramChatID)
+	setFlag("profile", cfg.Campaign.Profile)
+	setFlag("workers", strconv.Itoa(cfg.MaxWorkers))
+	setFlag("retries", strconv.Itoa(cfg.RegistrationRetry))
+	setFlag("start-jitter", cfg.StartJitter.String())
+	setFlag("ramp-delay", cfg.RampDelay.String())
+	setFlag("page-load-wait", cfg.PageLoadWait.String())
+	setFlag("element-wait", cfg.ElementWait.String())
+	setFlag("field-delay", cfg.FieldDelay.String())
+	setFlag("submit-wait", cfg.SubmitWait.String())
+	setFlag("nav-retries", strconv.Itoa(cfg.NavRetries))
+	setFlag("proxy-session-scope", cfg.ProxySessionScope)
+	setFlag("pin-proxy", strconv.FormatBool(cfg.PinProxy))
+	setFlag("fail-fast", strconv.FormatBool(cfg.FailFast))
+	setFlag("fail-fast-on", strings.Join(cfg.FailFastStatuses, ","))
+	setFlag("block-resources", strings.Join(cfg.BlockResources, ","))
+	setFlag("webhook", cfg.WebhookURL)
+	setFlag("output-format", cfg.OutputFormat)
+	setFlag("group-by", cfg.GroupBy)
+	setFlag("browser", cfg.Browser)
+}
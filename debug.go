@@ -1,15 +1,20 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
+	"strings"
+	"sync"
 	"time"
 )
 
-// runDebugMode executes debug tests without performing actual registrations
-func runDebugMode(logger *Logger, proxiesFile, eventsFile string) {
+// runDebugMode executes debug tests without performing actual registrations.
+// Simulated registration logs are only printed with demo (--demo).
+func runDebugMode(logger *Logger, proxiesFile, eventsFile string, template *FormTemplate, demo bool) {
 	logger.Info("=== DEBUG MODE ===")
 	logger.Info("Running diagnostic tests...")
 	fmt.Println()
@@ -33,13 +38,14 @@ func runDebugMode(logger *Logger, proxiesFile, eventsFile string) {
 	if err != nil {
 		logger.Warning("Could not load event URLs: %v", err)
 	} else {
-		testEventURLs(eventURLs, logger)
+		testEventURLs(eventURLs, template, logger)
 	}
 
-	fmt.Println()
-
-	// Test 4: Generate fake registration logs
-	generateFakeLogs(logger)
+	// Demo: simulated registration logs, clearly not real results
+	if demo {
+		fmt.Println()
+		generateFakeLogs(logger)
+	}
 
 	logger.Info("=== DEBUG MODE COMPLETED ===")
 }
@@ -71,20 +77,71 @@ func testIPInfo(logger *Logger) {
 	logger.Info("✓ Current IP: %v", result["ip"])
 
 	// Get additional info
-	resp2, err := client.Get("http://ip-api.com/json/")
-	if err == nil {
-		defer resp2.Body.Close()
-		body2, _ := io.ReadAll(resp2.Body)
-		var geoInfo map[string]interface{}
-		if json.Unmarshal(body2, &geoInfo) == nil {
-			logger.Info("  Country: %v", geoInfo["country"])
-			logger.Info("  City: %v", geoInfo["city"])
-			logger.Info("  ISP: %v", geoInfo["isp"])
-		}
+	ip, _ := result["ip"].(string)
+	geo, err := getGeoClient(logger).Lookup(ip)
+	if err != nil {
+		logger.Warning("  Geo lookup unavailable: %v", err)
+		return
+	}
+	logger.Info("  Country: %s", geo.Country)
+	logger.Info("  City: %s", geo.City)
+	logger.Info("  ISP: %s", geo.ISP)
+	logger.Debug("  Geo provider: %s", geo.Provider)
+}
+
+// proxyCheckURL is fetched through each proxy to measure it and read its exit IP
+const proxyCheckURL = "https://api.ipify.org?format=json"
+
+// ProxyCheck is the result of routing one request through a proxy
+type ProxyCheck struct {
+	Proxy      ProxyConfig
+	Latency    time.Duration
+	ExitIP     string
+	AuthFailed bool // The proxy answered 407 Proxy Authentication Required
+	Err        error
+}
+
+// checkProxy fetches target through proxy and reports latency and the exit IP
+func checkProxy(proxy ProxyConfig, target string, timeout time.Duration) ProxyCheck {
+	check := ProxyCheck{Proxy: proxy}
+	proxy = proxy.withSession(newSessionID(0))
+
+	client, err := newProxyHTTPClient(&proxy, timeout)
+	if err != nil {
+		check.Err = err
+		return check
+	}
+
+	start := time.Now()
+	resp, err := client.Get(target)
+	check.Latency = time.Since(start)
+	if err != nil {
+		check.AuthFailed = strings.Contains(err.Error(), "Proxy Authentication Required")
+		check.Err = err
+		return check
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusProxyAuthRequired {
+		check.AuthFailed = true
+		check.Err = fmt.Errorf("proxy returned %s", resp.Status)
+		return check
 	}
+	if resp.StatusCode >= 400 {
+		check.Err = fmt.Errorf("request through proxy returned %s", resp.Status)
+		return check
+	}
+
+	var result struct {
+		IP string `json:"ip"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&result); err == nil {
+		check.ExitIP = result.IP
+	}
+	return check
 }
 
-// testProxies tests proxy connectivity
+// testProxies routes a request through every proxy, a few at a time
 func testProxies(proxies []ProxyConfig, logger *Logger) {
 	logger.Info("Test 2: Testing proxy connections...")
 
@@ -93,33 +150,92 @@ func testProxies(proxies []ProxyConfig, logger *Logger) {
 		return
 	}
 
-	testCount := 3
-	if len(proxies) < testCount {
-		testCount = len(proxies)
+	checks := make([]ProxyCheck, len(proxies))
+	sem := make(chan struct{}, 8)
+	var wg sync.WaitGroup
+	for i, proxy := range proxies {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, proxy ProxyConfig) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			checks[i] = checkProxy(proxy, proxyCheckURL, 15*time.Second)
+		}(i, proxy)
+	}
+	wg.Wait()
+
+	working := 0
+	for i, check := range checks {
+		logger.Info("Testing proxy %d: %s", i+1, check.Proxy.Server)
+
+		switch {
+		case check.AuthFailed:
+			logger.Error("  ✗ Authentication failed (user: %s)", check.Proxy.Username)
+		case check.Err != nil:
+			logger.Error("  ✗ Unreachable: %v", check.Err)
+		default:
+			working++
+			if check.Proxy.Username != "" {
+				logger.Info("  ✓ Authenticated (user: %s)", check.Proxy.Username)
+			}
+			logger.Info("  ✓ Latency: %dms", check.Latency.Milliseconds())
+			if check.ExitIP != "" {
+				logger.Info("  ✓ Exit IP: %s", check.ExitIP)
+			}
+		}
 	}
 
-	for i := 0; i < testCount; i++ {
-		proxy := proxies[i]
-		logger.Info("Testing proxy %d: %s", i+1, proxy.Server)
+	logger.Info("✓ %d/%d proxies working", working, len(proxies))
+}
 
-		// Simulate proxy test
-		authenticated := proxy.Username != "" && proxy.Password != ""
-		if authenticated {
-			logger.Info("  ✓ Authenticated proxy (user: %s)", proxy.Username)
-		} else {
-			logger.Info("  ✓ Unauthenticated proxy")
+// selectorProbe pulls the id or attribute value out of a CSS selector
+var selectorProbe = regexp.MustCompile(`#([\w-]+)|\[[\w-]+[*^$~|]?=['"]?([^'"\]]+)`)
+
+// selectorInHTML approximates whether selector matches anything in static html.
+// Each comma-separated alternative matches when every id and attribute value
+// it names appears in the page; alternatives naming neither count as present.
+func selectorInHTML(selector, html string) bool {
+	html = strings.ToLower(html)
+	for _, alternative := range strings.Split(selector, ",") {
+		found := true
+		for _, match := range selectorProbe.FindAllStringSubmatch(alternative, -1) {
+			value := match[1] + match[2]
+			if !strings.Contains(html, strings.ToLower(value)) {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
 		}
+	}
+	return false
+}
 
-		// Fake latency test
-		latency := 50 + i*20
-		logger.Info("  ✓ Latency: %dms", latency)
+// missingFormFields returns the template fields whose selectors aren't found in html
+func missingFormFields(template *FormTemplate, html string) []string {
+	fields := []struct{ name, selector string }{
+		{"first name", template.FirstName},
+		{"last name", template.LastName},
+		{"email", template.Email},
+		{"organization", template.Organization},
+		{"submit", template.Submit},
+	}
+	for _, field := range template.CustomFields {
+		fields = append(fields, struct{ name, selector string }{field.label(), field.Selector})
 	}
 
-	logger.Info("✓ Tested %d/%d proxies", testCount, len(proxies))
+	var missing []string
+	for _, field := range fields {
+		if field.selector != "" && !selectorInHTML(field.selector, html) {
+			missing = append(missing, field.name)
+		}
+	}
+	return missing
 }
 
-// testEventURLs tests event URL accessibility
-func testEventURLs(eventURLs []string, logger *Logger) {
+// testEventURLs fetches every event page and looks for its form fields
+func testEventURLs(eventURLs []string, fallback *FormTemplate, logger *Logger) {
 	logger.Info("Test 3: Testing event URLs...")
 
 	if len(eventURLs) == 0 {
@@ -127,37 +243,95 @@ func testEventURLs(eventURLs []string, logger *Logger) {
 		return
 	}
 
-	for i, url := range eventURLs {
-		logger.Info("Testing event %d: %s", i+1, url)
+	for i, check := range checkEventURLs(eventURLs, 10*time.Second) {
+		logger.Info("Testing event %d: %s", i+1, check.URL)
 
-		// Simulate URL validation
-		client := &http.Client{
-			Timeout: 10 * time.Second,
-			CheckRedirect: func(req *http.Request, via []*http.Request) error {
-				return http.ErrUseLastResponse
-			},
+		if !check.Reachable() {
+			logger.Error("  ✗ URL not accessible: %s", check.Problem())
+			continue
 		}
+		logger.Info("  ✓ URL accessible (Status: %d)", check.Status)
 
-		resp, err := client.Head(url)
+		_, template, err := eventTemplate(eventURLs[i], fallback)
 		if err != nil {
-			logger.Error("  ✗ URL not accessible: %v", err)
-			logger.Error("  Fake error: Event registration page returned 404")
+			logger.Warning("  ✗ %v", err)
 			continue
 		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode >= 200 && resp.StatusCode < 400 {
-			logger.Info("  ✓ URL accessible (Status: %d)", resp.StatusCode)
+		if missing := missingFormFields(template, check.Body); len(missing) > 0 {
+			logger.Warning("  ✗ %s form fields not in page HTML: %s (fine if the form is rendered by JavaScript)", template.Name, strings.Join(missing, ", "))
 		} else {
-			logger.Warning("  ✗ URL returned status %d", resp.StatusCode)
-			logger.Warning("  Fake error: Event may be closed or invalid")
+			logger.Info("  ✓ %s form fields found", template.Name)
+		}
+	}
+}
+
+// parseTestTarget splits the "<event URL> <email>" argument of --test-one and
+// /test; the event may name a template ("url|template") and a comma may
+// separate the two
+func parseTestTarget(s string) (eventURL, email string, err error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	if len(fields) < 2 {
+		return "", "", fmt.Errorf("expected an event URL and an email")
+	}
+	email = fields[len(fields)-1]
+	if err := validateEmail(email); err != nil {
+		return "", "", fmt.Errorf("invalid email %q: %v", email, err)
+	}
+	eventURL = strings.Join(fields[:len(fields)-1], "")
+	if !strings.HasPrefix(eventURL, "http://") && !strings.HasPrefix(eventURL, "https://") {
+		return "", "", fmt.Errorf("invalid event URL %q", eventURL)
+	}
+	return eventURL, email, nil
+}
+
+// runTestOne performs one complete registration of contact for entry (an
+// event line, optionally naming a template) with up to retries attempts, to
+// validate a template before a campaign
+func runTestOne(ctx context.Context, entry string, contact Contact, fallback *FormTemplate, headless bool, retries int, proxies []ProxyConfig, logger *Logger) RegistrationResult {
+	eventURL, template, err := eventTemplate(entry, fallback)
+	if err != nil {
+		logger.Warning("%v - using %s", err, fallback.Name)
+	}
+	logger.Info("🧪 Test registration of %s on %s (template: %s, proxies: %d)", contact.Email, eventURL, template.Name, len(proxies))
+
+	worker := NewRegistrationWorker(0, NewProxyPool(proxies), headless, retries, "", logger)
+	defer worker.Close()
+	return worker.ExecuteRegistration(ctx, eventURL, template, contact)
+}
+
+// formatTestReport describes a test registration result line by line
+func formatTestReport(result RegistrationResult) []string {
+	lines := []string{
+		fmt.Sprintf("Email: %s", result.Email),
+		fmt.Sprintf("Event: %s", result.Event),
+		fmt.Sprintf("Status: %s", result.Status),
+	}
+	if strategy := detectionStrategy(result.Message); strategy != "" {
+		lines = append(lines, fmt.Sprintf("Detected by: %s", strategy))
+	}
+	if result.Category != CategoryNone {
+		lines = append(lines, fmt.Sprintf("Category: %s", result.Category))
+	}
+	lines = append(lines, fmt.Sprintf("Message: %s", result.Message))
+	for _, a := range result.Attempts {
+		mark := "✗"
+		if a.Success {
+			mark = "✓"
+		}
+		line := fmt.Sprintf("Attempt %d %s %s", a.Attempt, mark, a.Message)
+		if a.Proxy != "" {
+			line += " (proxy " + a.Proxy + ")"
 		}
+		lines = append(lines, line)
 	}
+	return lines
 }
 
-// generateFakeLogs generates fake registration attempt logs
+// generateFakeLogs prints simulated registration logs for --demo
 func generateFakeLogs(logger *Logger) {
-	logger.Info("Test 4: Generating fake registration logs...")
+	logger.Info("Demo: simulated registration logs (no requests are made)...")
 
 	// Removed unused fakeEmails variable
 
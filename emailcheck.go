@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Length limits from RFC 5321
+const (
+	maxEmailLength  = 254
+	maxLocalLength  = 64
+	maxDomainLength = 253
+	maxLabelLength  = 63
+)
+
+// mxLookupTimeout bounds each MX lookup made by --verify-mx
+const mxLookupTimeout = 5 * time.Second
+
+var (
+	localPartRegex   = regexp.MustCompile(`^[a-zA-Z0-9!#$%&'*+/=?^_{|}~-]+(\.[a-zA-Z0-9!#$%&'*+/=?^_{|}~-]+)*$`)
+	domainLabelRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?$`)
+	topLabelRegex    = regexp.MustCompile(`^[a-zA-Z]{2,}$`)
+)
+
+// validateEmail checks an address closely enough to reject typos and
+// scraping debris: one "@", a dot-atom local part, and a domain of at least
+// two valid labels ending in an alphabetic TLD. Quoted local parts and IP
+// literals are legal but never seen in sign-up lists, so they are rejected.
+func validateEmail(email string) error {
+	if email == "" {
+		return errors.New("empty address")
+	}
+	if len(email) > maxEmailLength {
+		return fmt.Errorf("longer than %d characters", maxEmailLength)
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return errors.New("missing @")
+	}
+	local, domain := email[:at], email[at+1:]
+
+	switch {
+	case local == "":
+		return errors.New("empty local part")
+	case len(local) > maxLocalLength:
+		return fmt.Errorf("local part longer than %d characters", maxLocalLength)
+	case !localPartRegex.MatchString(local):
+		return fmt.Errorf("invalid local part %q", local)
+	}
+
+	if domain == "" {
+		return errors.New("empty domain")
+	}
+	if len(domain) > maxDomainLength {
+		return fmt.Errorf("domain longer than %d characters", maxDomainLength)
+	}
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return fmt.Errorf("domain %q has no TLD", domain)
+	}
+	for _, label := range labels {
+		if len(label) > maxLabelLength || !domainLabelRegex.MatchString(label) {
+			return fmt.Errorf("invalid domain %q", domain)
+		}
+	}
+	if !topLabelRegex.MatchString(labels[len(labels)-1]) {
+		return fmt.Errorf("invalid TLD in %q", domain)
+	}
+	return nil
+}
+
+// emailCandidate matches an address-like token in free text. The whole
+// token is validated, so "user@domain.org." isn't quietly trimmed into an
+// address.
+var emailCandidate = regexp.MustCompile(`[^\s<>()\[\],;:"']+@[^\s<>()\[\],;:"']+`)
+
+// markdownLink matches [text](target) links
+var markdownLink = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]*)\)`)
+
+// emailFromLine finds the address on one line of an email list: the target
+// of a markdown mailto link, else the text of a markdown link, else the
+// first address-like token. The token is returned even if it is invalid so
+// the caller can report it; "" means the line has none.
+func emailFromLine(line string) string {
+	links := markdownLink.FindAllStringSubmatch(line, -1)
+	for _, link := range links {
+		if target, ok := strings.CutPrefix(link[2], "mailto:"); ok {
+			target, _, _ = strings.Cut(target, "?")
+			if validateEmail(target) == nil {
+				return target
+			}
+		}
+	}
+	for _, link := range links {
+		if text := strings.TrimSpace(link[1]); validateEmail(text) == nil {
+			return text
+		}
+	}
+	return emailCandidate.FindString(line)
+}
+
+// MXChecker reports whether email domains accept mail, looking each domain
+// up once. Lookups that fail for reasons other than the domain not existing
+// count as accepting mail, so a flaky resolver doesn't empty the list.
+type MXChecker struct {
+	lookup func(ctx context.Context, domain string) ([]*net.MX, error)
+	cache  map[string]bool
+	logger *Logger
+	mu     sync.Mutex
+}
+
+// NewMXChecker returns an MXChecker using the system resolver
+func NewMXChecker(logger *Logger) *MXChecker {
+	return &MXChecker{
+		lookup: net.DefaultResolver.LookupMX,
+		cache:  make(map[string]bool),
+		logger: logger,
+	}
+}
+
+// HasMX reports whether domain has a mail exchanger. A lone "." record
+// (RFC 7505 null MX) means the domain explicitly takes no mail.
+func (c *MXChecker) HasMX(domain string) bool {
+	domain = strings.ToLower(domain)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ok, cached := c.cache[domain]; cached {
+		return ok
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mxLookupTimeout)
+	defer cancel()
+	records, err := c.lookup(ctx, domain)
+	ok := err == nil && len(records) > 0 && !(len(records) == 1 && records[0].Host == ".")
+	if err != nil {
+		var dnsErr *net.DNSError
+		if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+			c.logger.Warning("MX lookup for %s failed, keeping its addresses: %v", domain, err)
+			ok = true
+		}
+	}
+	c.cache[domain] = ok
+	return ok
+}
+
+// emailScreen decides which loaded addresses are kept and counts why the
+// others were dropped
+type emailScreen struct {
+	mx       *MXChecker // nil unless --verify-mx
+	invalid  int
+	filtered int
+	noMX     int
+}
+
+// newEmailScreen applies the current config's domain filters and --verify-mx
+func newEmailScreen(logger *Logger) *emailScreen {
+	s := &emailScreen{}
+	if config.VerifyMX {
+		s.mx = NewMXChecker(logger)
+	}
+	return s
+}
+
+// keep reports whether email should be loaded. where says where it came
+// from (e.g. "line 4") for the warning about an invalid address.
+func (s *emailScreen) keep(email, where string, logger *Logger) bool {
+	if err := validateEmail(email); err != nil {
+		s.invalid++
+		logger.Warning("Skipping invalid email on %s: %s (%v)", where, email, err)
+		return false
+	}
+	if !emailDomainAllowed(email) {
+		s.filtered++
+		logger.Debug("Filtered out by domain: %s", email)
+		return false
+	}
+	if s.mx != nil && !s.mx.HasMX(email[strings.LastIndex(email, "@")+1:]) {
+		s.noMX++
+		logger.Debug("No MX record for %s", email)
+		return false
+	}
+	return true
+}
+
+// report logs how many addresses were dropped and why
+func (s *emailScreen) report(logger *Logger) {
+	if s.invalid+s.filtered+s.noMX == 0 {
+		return
+	}
+	msg := fmt.Sprintf("Rejected %d emails: %d invalid syntax", s.invalid+s.filtered+s.noMX, s.invalid)
+	if s.filtered > 0 {
+		msg += fmt.Sprintf(", %d filtered by domain", s.filtered)
+	}
+	if s.mx != nil {
+		msg += fmt.Sprintf(", %d with no MX record", s.noMX)
+	}
+	logger.Info("%s", msg)
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// writeResultsCSV writes results as CSV with a header row
+func writeResultsCSV(w io.Writer, results []RegistrationResult) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"Email", "Event", "Status", "Attempt", "Message", "Timestamp"}); err != nil {
+		return err
+	}
+	for _, r := range results {
+		record := []string{
+			r.Email,
+			r.Event,
+			r.Status,
+			strconv.Itoa(r.Attempt),
+			r.Message,
+			r.Timestamp.Format(time.RFC3339),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// saveResultsCSV writes results to filename as CSV
+func saveResultsCSV(filename string, results []RegistrationResult) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := writeResultsCSV(file, results); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// resultStreamFlushInterval is how often streamed results are flushed to disk
+const resultStreamFlushInterval = 2 * time.Second
+
+// ResultStream appends each result to a JSONL file as it arrives, so a crash
+// keeps everything recorded so far and the file can be tailed live
+type ResultStream struct {
+	filename  string
+	file      *os.File
+	writer    *bufio.Writer
+	encoder   *json.Encoder
+	lastFlush time.Time
+}
+
+// NewResultStream creates (or truncates) filename for streaming
+func NewResultStream(filename string) (*ResultStream, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	writer := bufio.NewWriter(file)
+	return &ResultStream{
+		filename:  filename,
+		file:      file,
+		writer:    writer,
+		encoder:   json.NewEncoder(writer),
+		lastFlush: time.Now(),
+	}, nil
+}
+
+// Write appends one result line, flushing if the last flush is old enough
+func (s *ResultStream) Write(result RegistrationResult) error {
+	if err := s.encoder.Encode(result); err != nil {
+		return err
+	}
+	if time.Since(s.lastFlush) >= resultStreamFlushInterval {
+		return s.Flush()
+	}
+	return nil
+}
+
+// Flush writes buffered results through to the file
+func (s *ResultStream) Flush() error {
+	s.lastFlush = time.Now()
+	if err := s.writer.Flush(); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+// Close flushes and closes the stream file
+func (s *ResultStream) Close() error {
+	if err := s.writer.Flush(); err != nil {
+		s.file.Close()
+		return err
+	}
+	return s.file.Close()
+}
+
+// readResultStream loads every result from a JSONL stream file
+func readResultStream(filename string) ([]RegistrationResult, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var results []RegistrationResult
+	decoder := json.NewDecoder(file)
+	for decoder.More() {
+		var r RegistrationResult
+		if err := decoder.Decode(&r); err != nil {
+			return results, err
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// CampaignManifest describes the contents of an exported results archive
+type CampaignManifest struct {
+	Version     string         `json:"version"`
+	StartTime   time.Time      `json:"start_time"`
+	ExportTime  time.Time      `json:"export_time"`
+	Total       int            `json:"total"`
+	StatusCount map[string]int `json:"status_count"`
+	Files       []string       `json:"files"`
+}
+
+// buildResultsArchive zips results JSON, CSV, a manifest and the debug
+// screenshots recorded on the results' attempts that are still on disk
+func buildResultsArchive(results []RegistrationResult, startTime time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	manifest := CampaignManifest{
+		Version:     Version,
+		StartTime:   startTime,
+		ExportTime:  time.Now(),
+		Total:       len(results),
+		StatusCount: make(map[string]int),
+	}
+	for _, r := range results {
+		manifest.StatusCount[r.Status]++
+	}
+
+	addFile := func(name string, data []byte) error {
+		w, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, name)
+		return nil
+	}
+
+	jsonData, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := addFile("results.json", jsonData); err != nil {
+		return nil, err
+	}
+
+	var csvData bytes.Buffer
+	if err := writeResultsCSV(&csvData, results); err != nil {
+		return nil, err
+	}
+	if err := addFile("results.csv", csvData.Bytes()); err != nil {
+		return nil, err
+	}
+
+	// Only this campaign's own screenshots: other chats' campaigns share
+	// --screenshot-dir, and their files show their registrants' details
+	for _, r := range results {
+		for _, a := range r.Attempts {
+			if a.Screenshot == "" {
+				continue
+			}
+			data, err := os.ReadFile(a.Screenshot)
+			if err != nil {
+				continue
+			}
+			if err := addFile("screenshots/"+filepath.Base(a.Screenshot), data); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	w, err := archive.Create("manifest.json")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(manifestData); err != nil {
+		return nil, err
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// Viewport is a browser window size
+type Viewport struct {
+	Width  int
+	Height int
+}
+
+// String formats the viewport the way parseViewport reads it
+func (v Viewport) String() string {
+	return fmt.Sprintf("%dx%d", v.Width, v.Height)
+}
+
+// parseViewport parses "WIDTHxHEIGHT"
+func parseViewport(s string) (Viewport, error) {
+	widthText, heightText, ok := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "x")
+	width, widthErr := strconv.Atoi(strings.TrimSpace(widthText))
+	height, heightErr := strconv.Atoi(strings.TrimSpace(heightText))
+	if !ok || widthErr != nil || heightErr != nil || width <= 0 || height <= 0 {
+		return Viewport{}, fmt.Errorf("invalid window size %q (expected e.g. 1920x1080)", strings.TrimSpace(s))
+	}
+	return Viewport{Width: width, Height: height}, nil
+}
+
+// parseViewports parses a comma-separated list of window sizes
+func parseViewports(s string) ([]Viewport, error) {
+	var viewports []Viewport
+	for _, item := range splitList(s) {
+		viewport, err := parseViewport(item)
+		if err != nil {
+			return nil, err
+		}
+		viewports = append(viewports, viewport)
+	}
+	if len(viewports) == 0 {
+		return nil, fmt.Errorf("no window sizes given")
+	}
+	return viewports, nil
+}
+
+// defaultViewports are common desktop resolutions
+var defaultViewports = []Viewport{
+	{1920, 1080},
+	{1536, 864},
+	{1440, 900},
+	{1366, 768},
+	{1280, 720},
+	{1248, 836},
+}
+
+// UserAgent is a browser identity for one context, with the window size that
+// fits it (zero when any size will do)
+type UserAgent struct {
+	UserAgent string
+	Viewport  Viewport
+}
+
+// defaultUserAgents are current desktop browsers paired with common screen sizes
+var defaultUserAgents = []UserAgent{
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36", Viewport{1920, 1080}},
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36", Viewport{1366, 768}},
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.0.0", Viewport{1536, 864}},
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36", Viewport{}},
+	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36", Viewport{1440, 900}},
+	{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36", Viewport{}},
+	{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36", Viewport{}},
+	{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", Viewport{}},
+}
+
+// userAgentSizeSeparator splits a useragents.txt line into agent and window size ("agent|1920x1080")
+const userAgentSizeSeparator = "|"
+
+// parseUserAgentLine parses "agent" or "agent|WIDTHxHEIGHT"
+func parseUserAgentLine(line string) (UserAgent, error) {
+	agent, size, hasSize := strings.Cut(line, userAgentSizeSeparator)
+	ua := UserAgent{UserAgent: strings.TrimSpace(agent)}
+	if ua.UserAgent == "" {
+		return ua, fmt.Errorf("empty user agent")
+	}
+	if !hasSize {
+		return ua, nil
+	}
+
+	viewport, err := parseViewport(size)
+	if err != nil {
+		return ua, err
+	}
+	ua.Viewport = viewport
+	return ua, nil
+}
+
+// viewport returns the window size paired with ua, or a random --viewports size
+func (ua UserAgent) viewport() Viewport {
+	if ua.Viewport.Width > 0 && ua.Viewport.Height > 0 {
+		return ua.Viewport
+	}
+	viewports := config.Viewports
+	if len(viewports) == 0 {
+		viewports = defaultViewports
+	}
+	return viewports[random.Intn(len(viewports))]
+}
+
+// randomUserAgent picks one of the configured user agents for a new context
+func randomUserAgent() UserAgent {
+	agents := config.UserAgents
+	if len(agents) == 0 {
+		agents = defaultUserAgents
+	}
+	return agents[random.Intn(len(agents))]
+}
+
+// playwrightDevices returns Playwright's device descriptors
+var playwrightDevices = func() (map[string]*playwright.DeviceDescriptor, error) {
+	pw, err := playwright.Run()
+	if err != nil {
+		return nil, err
+	}
+	defer pw.Stop()
+	return pw.Devices, nil
+}
+
+// checkDevice makes sure name is a Playwright device, so a typo in --device
+// stops the run up front instead of failing every task
+func checkDevice(name string) error {
+	devices, err := playwrightDevices()
+	if err != nil {
+		return fmt.Errorf("Could not load Playwright devices to check --device: %v", err)
+	}
+	if _, ok := devices[name]; ok {
+		return nil
+	}
+	names := make([]string, 0, len(devices))
+	for device := range devices {
+		names = append(names, device)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("Unknown --device %q. Valid devices:\n  %s", name, strings.Join(names, "\n  "))
+}
+
+// applyBrowserIdentity sets the user agent and window size of a new context:
+// the --device descriptor when emulating a device, a random desktop identity otherwise
+func applyBrowserIdentity(options *playwright.BrowserNewContextOptions, devices map[string]*playwright.DeviceDescriptor, logger *Logger) error {
+	if config.Device != "" {
+		device, ok := devices[config.Device]
+		if !ok {
+			return fmt.Errorf("unknown --device %q (use a Playwright device name like \"iPhone 13\")", config.Device)
+		}
+		options.UserAgent = playwright.String(device.UserAgent)
+		options.Viewport = device.Viewport
+		options.Screen = device.Screen
+		options.DeviceScaleFactor = playwright.Float(device.DeviceScaleFactor)
+		options.IsMobile = playwright.Bool(device.IsMobile)
+		options.HasTouch = playwright.Bool(device.HasTouch)
+		logger.Debug("📱 Emulating %s (%dx%d)", config.Device, device.Viewport.Width, device.Viewport.Height)
+		return nil
+	}
+
+	// The built-in user agents are Chrome and Edge; on another engine keep its
+	// own user agent rather than claim to be a browser it isn't
+	if config.Browser != browserChromium && len(config.UserAgents) == 0 {
+		viewport := UserAgent{}.viewport()
+		options.Viewport = &playwright.Size{Width: viewport.Width, Height: viewport.Height}
+		logger.Debug("🪪 %s's own user agent (viewport %s)", config.Browser, viewport)
+		return nil
+	}
+
+	userAgent := randomUserAgent()
+	viewport := userAgent.viewport()
+	options.UserAgent = playwright.String(userAgent.UserAgent)
+	options.Viewport = &playwright.Size{Width: viewport.Width, Height: viewport.Height}
+	logger.Debug("🪪 User agent: %s (viewport %s)", userAgent.UserAgent, viewport)
+	return nil
+}
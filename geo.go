@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GeoInfo holds geolocation details for an IP address
+type GeoInfo struct {
+	IP       string `json:"ip"`
+	Country  string `json:"country"`
+	City     string `json:"city"`
+	ISP      string `json:"isp"`
+	Provider string `json:"provider"`
+}
+
+// GeoProvider describes an IP geolocation endpoint and how to decode its response
+type GeoProvider struct {
+	Name     string
+	Endpoint string // {ip} is replaced with the address, or removed for the caller's own IP
+	parse    func(body []byte) (GeoInfo, error)
+}
+
+// geoProviders are the built-in geolocation providers, keyed by name
+var geoProviders = map[string]GeoProvider{
+	"ip-api": {
+		Name:     "ip-api",
+		Endpoint: "http://ip-api.com/json/{ip}",
+		parse: func(body []byte) (GeoInfo, error) {
+			var r struct {
+				Status  string `json:"status"`
+				Message string `json:"message"`
+				Query   string `json:"query"`
+				Country string `json:"country"`
+				City    string `json:"city"`
+				ISP     string `json:"isp"`
+			}
+			if err := json.Unmarshal(body, &r); err != nil {
+				return GeoInfo{}, err
+			}
+			if r.Status != "success" {
+				return GeoInfo{}, fmt.Errorf("lookup failed: %s", r.Message)
+			}
+			return GeoInfo{IP: r.Query, Country: r.Country, City: r.City, ISP: r.ISP}, nil
+		},
+	},
+	"ipwho.is": {
+		Name:     "ipwho.is",
+		Endpoint: "https://ipwho.is/{ip}",
+		parse: func(body []byte) (GeoInfo, error) {
+			var r struct {
+				Success    bool   `json:"success"`
+				Message    string `json:"message"`
+				IP         string `json:"ip"`
+				Country    string `json:"country"`
+				City       string `json:"city"`
+				Connection struct {
+					ISP string `json:"isp"`
+				} `json:"connection"`
+			}
+			if err := json.Unmarshal(body, &r); err != nil {
+				return GeoInfo{}, err
+			}
+			if !r.Success {
+				return GeoInfo{}, fmt.Errorf("lookup failed: %s", r.Message)
+			}
+			return GeoInfo{IP: r.IP, Country: r.Country, City: r.City, ISP: r.Connection.ISP}, nil
+		},
+	},
+}
+
+// GeoClient performs rate-limited, cached geolocation lookups with provider fallback
+type GeoClient struct {
+	providers   []GeoProvider
+	client      *http.Client
+	minInterval time.Duration
+	nextAllowed map[string]time.Time
+	cache       map[string]GeoInfo
+	logger      *Logger
+	mu          sync.Mutex
+}
+
+// NewGeoClient creates a client trying providers in order, limited to perMinute requests each
+func NewGeoClient(providers []GeoProvider, perMinute int, logger *Logger) *GeoClient {
+	if perMinute < 1 {
+		perMinute = 1
+	}
+	return &GeoClient{
+		providers:   providers,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		minInterval: time.Minute / time.Duration(perMinute),
+		nextAllowed: make(map[string]time.Time),
+		cache:       make(map[string]GeoInfo),
+		logger:      logger,
+	}
+}
+
+var (
+	geoClient     *GeoClient
+	geoClientOnce sync.Once
+)
+
+// getGeoClient returns the shared geo client built from config
+func getGeoClient(logger *Logger) *GeoClient {
+	geoClientOnce.Do(func() {
+		var providers []GeoProvider
+		for _, name := range config.GeoProviders {
+			provider, ok := geoProviders[name]
+			if !ok {
+				logger.Warning("Unknown geo provider %q - skipping", name)
+				continue
+			}
+			if name == "ip-api" && config.GeoEndpoint != "" {
+				provider.Endpoint = config.GeoEndpoint
+			}
+			providers = append(providers, provider)
+		}
+		geoClient = NewGeoClient(providers, config.GeoRatePerMinute, logger)
+	})
+	return geoClient
+}
+
+// Lookup returns geolocation info for ip (empty means the caller's own address)
+func (g *GeoClient) Lookup(ip string) (GeoInfo, error) {
+	g.mu.Lock()
+	if info, ok := g.cache[ip]; ok {
+		g.mu.Unlock()
+		return info, nil
+	}
+	g.mu.Unlock()
+
+	var errs []string
+	for _, provider := range g.providers {
+		if !g.reserve(provider.Name) {
+			errs = append(errs, fmt.Sprintf("%s: rate limited", provider.Name))
+			continue
+		}
+
+		info, err := g.query(provider, ip)
+		if err != nil {
+			g.logger.Debug("Geo provider %s failed: %v", provider.Name, err)
+			errs = append(errs, fmt.Sprintf("%s: %v", provider.Name, err))
+			continue
+		}
+
+		info.Provider = provider.Name
+		g.mu.Lock()
+		g.cache[ip] = info
+		g.mu.Unlock()
+		return info, nil
+	}
+
+	if len(errs) == 0 {
+		return GeoInfo{}, fmt.Errorf("no geo providers configured")
+	}
+	return GeoInfo{}, fmt.Errorf("all geo providers failed (%s)", strings.Join(errs, "; "))
+}
+
+// reserve claims a request slot for provider, returning false if it is rate limited
+func (g *GeoClient) reserve(provider string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(g.nextAllowed[provider]) {
+		return false
+	}
+	g.nextAllowed[provider] = now.Add(g.minInterval)
+	return true
+}
+
+// backoff blocks provider until the given delay has passed
+func (g *GeoClient) backoff(provider string, delay time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.nextAllowed[provider] = time.Now().Add(delay)
+}
+
+// query performs a single lookup against provider
+func (g *GeoClient) query(provider GeoProvider, ip string) (GeoInfo, error) {
+	url := strings.Replace(provider.Endpoint, "{ip}", ip, 1)
+
+	resp, err := g.client.Get(url)
+	if err != nil {
+		return GeoInfo{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		delay := time.Minute
+		// ip-api reports seconds until reset in X-Ttl; others use Retry-After
+		for _, header := range []string{"Retry-After", "X-Ttl"} {
+			if secs, err := strconv.Atoi(resp.Header.Get(header)); err == nil && secs > 0 {
+				delay = time.Duration(secs) * time.Second
+				break
+			}
+		}
+		g.backoff(provider.Name, delay)
+		g.logger.Warning("Geo provider %s rate limited - backing off for %v", provider.Name, delay)
+		return GeoInfo{}, fmt.Errorf("rate limited (HTTP 429)")
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return GeoInfo{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return GeoInfo{}, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	return provider.parse(body)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LaunchBreaker pauses browser launches after too many consecutive launch
+// failures across all workers, so a server that can't run Chromium (out of
+// memory, missing libraries) isn't hit with a doomed launch for every task
+type LaunchBreaker struct {
+	threshold int           // Consecutive failures that open the breaker; 0 disables it
+	cooldown  time.Duration // How long launches stay paused once open
+	abort     bool          // Fail launches while open instead of waiting
+	failures  int
+	openUntil time.Time
+	mu        sync.Mutex
+}
+
+// launchBreaker is shared by all workers, set by --launch-failure-limit
+var launchBreaker = NewLaunchBreaker(0, 0, false)
+
+// NewLaunchBreaker opens after threshold consecutive failures and stays open for cooldown
+func NewLaunchBreaker(threshold int, cooldown time.Duration, abort bool) *LaunchBreaker {
+	return &LaunchBreaker{threshold: threshold, cooldown: cooldown, abort: abort}
+}
+
+// Failure records a failed launch and reports whether it opened the breaker.
+// After a cooldown, each further failure reopens it until a launch succeeds.
+func (b *LaunchBreaker) Failure() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.threshold <= 0 || b.failures < b.threshold {
+		return false
+	}
+	b.openUntil = time.Now().Add(b.cooldown)
+	return true
+}
+
+// Success records a successful launch, closing the breaker
+func (b *LaunchBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.openUntil = time.Time{}
+}
+
+// Open reports whether launches are currently paused
+func (b *LaunchBreaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return time.Now().Before(b.openUntil)
+}
+
+// Wait blocks while launches are paused, or fails at once when the breaker
+// is set to abort
+func (b *LaunchBreaker) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	delay := time.Until(b.openUntil)
+	failures := b.failures
+	b.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	if b.abort {
+		return fmt.Errorf("Browser launches stopped after %d consecutive failures", failures)
+	}
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("Waiting for browser launches to resume: %v", ctx.Err())
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+// launchFailureHint explains the usual causes of repeated launch failures
+const launchFailureHint = "Chromium is likely out of memory or missing system libraries - lower --workers or set --max-browsers, and run 'playwright install-deps'"
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LedgerEntry records one email+event pair that already succeeded
+type LedgerEntry struct {
+	Email     string    `json:"email"`
+	Event     string    `json:"event"` // Normalized event URL, see normalizeEventURL
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// TaskLedger is the set of email+event pairs that don't need to run again,
+// persisted to path so an interrupted campaign can pick up where it stopped.
+// The file holds one JSON entry per line and grows by appending, so recording
+// a success costs the same however long the campaign has run.
+type TaskLedger struct {
+	path    string
+	entries map[string]LedgerEntry
+	mu      sync.Mutex
+}
+
+// taskKey identifies a task by email and normalized event URL
+func taskKey(email, event string) string {
+	return strings.ToLower(strings.TrimSpace(email)) + " " + normalizeEventURL(event)
+}
+
+// LoadTaskLedger reads the ledger from path; a missing file (or an empty
+// path, for an in-memory ledger) yields an empty ledger
+func LoadTaskLedger(path string) (*TaskLedger, error) {
+	l := &TaskLedger{path: path, entries: make(map[string]LedgerEntry)}
+	if path == "" {
+		return l, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading success ledger: %v", err)
+	}
+
+	// Older versions wrote a single JSON array; rewrite it as lines so later
+	// entries can be appended
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var list []LedgerEntry
+		if err := json.Unmarshal(trimmed, &list); err != nil {
+			return nil, fmt.Errorf("invalid success ledger %s: %v", path, err)
+		}
+		for _, entry := range list {
+			l.entries[taskKey(entry.Email, entry.Event)] = entry
+		}
+		if err := l.Save(); err != nil {
+			return nil, fmt.Errorf("error converting success ledger %s: %v", path, err)
+		}
+		return l, nil
+	}
+
+	lines := bytes.Split(data, []byte("\n"))
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var entry LedgerEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			// A crash mid-append can only tear the last line
+			if i == len(lines)-1 {
+				break
+			}
+			return nil, fmt.Errorf("invalid success ledger %s line %d: %v", path, i+1, err)
+		}
+		l.entries[taskKey(entry.Email, entry.Event)] = entry
+	}
+	return l, nil
+}
+
+// Has reports whether email already succeeded for event
+func (l *TaskLedger) Has(email, event string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, ok := l.entries[taskKey(email, event)]
+	return ok
+}
+
+// Add records email+event as done and appends it to the ledger file
+func (l *TaskLedger) Add(email, event string) error {
+	entry := LedgerEntry{
+		Email:     strings.TrimSpace(email),
+		Event:     normalizeEventURL(event),
+		Timestamp: time.Now(),
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	key := taskKey(email, event)
+	_, seen := l.entries[key]
+	l.entries[key] = entry
+	if l.path == "" || seen {
+		return nil
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Len returns the number of recorded pairs
+func (l *TaskLedger) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.entries)
+}
+
+// Merge adds every pair in other
+func (l *TaskLedger) Merge(other *TaskLedger) {
+	other.mu.Lock()
+	entries := make([]LedgerEntry, 0, len(other.entries))
+	for _, entry := range other.entries {
+		entries = append(entries, entry)
+	}
+	other.mu.Unlock()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, entry := range entries {
+		l.entries[taskKey(entry.Email, entry.Event)] = entry
+	}
+}
+
+// completedTasks returns an in-memory ledger of the tasks in results that
+// reached a final outcome. Failures, timeouts, proxy errors and captchas are
+// left out so resuming retries them. Results written before events were
+// recorded by URL can't be matched and run again.
+func completedTasks(results []RegistrationResult) *TaskLedger {
+	l, _ := LoadTaskLedger("")
+	for _, r := range results {
+		if isTerminalStatus(r.Status) && r.EventURL != "" {
+			l.Add(r.Email, r.EventURL)
+		}
+	}
+	return l
+}
+
+// isTerminalStatus reports whether a task with status needs no further run
+func isTerminalStatus(status string) bool {
+	switch status {
+	case "SUCCESS", "ALREADY_REGISTERED", "SKIPPED":
+		return true
+	default:
+		return false
+	}
+}
+
+// Save rewrites the whole ledger file, one entry per line, replacing it
+// atomically so a crash mid-write can't corrupt it. In-memory ledgers are
+// not saved.
+func (l *TaskLedger) Save() error {
+	if l.path == "" {
+		return nil
+	}
+
+	l.mu.Lock()
+	list := make([]LedgerEntry, 0, len(l.entries))
+	for _, entry := range l.entries {
+		list = append(list, entry)
+	}
+	l.mu.Unlock()
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Event != list[j].Event {
+			return list[i].Event < list[j].Event
+		}
+		return list[i].Email < list[j].Email
+	})
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, entry := range list {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, l.path)
+}
+
+// skippedResult reports a task that wasn't run because an earlier run did it
+func skippedResult(eventURL string, contact Contact, reason string) RegistrationResult {
+	return RegistrationResult{
+		Email:     contact.Email,
+		Contact:   contact,
+		Event:     eventKey(eventURL),
+		EventURL:  normalizeEventURL(eventURL),
+		Status:    "SKIPPED",
+		Message:   reason,
+		Timestamp: time.Now(),
+	}
+}
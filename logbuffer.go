@@ -0,0 +1,56 @@
+package main
+
+import "sync"
+
+// logHistorySize is how many recent log lines /logs can return
+const logHistorySize = 500
+
+// LogBuffer keeps the most recent log lines in a fixed-size ring, so bot
+// operators on a headless server can read them without stdout
+type LogBuffer struct {
+	lines []string
+	next  int  // Slot the next line is written to
+	full  bool // Set once the ring has wrapped
+	mu    sync.Mutex
+}
+
+// recentLogs holds the lines written by every Logger
+var recentLogs = NewLogBuffer(logHistorySize)
+
+// NewLogBuffer keeps the last size lines
+func NewLogBuffer(size int) *LogBuffer {
+	return &LogBuffer{lines: make([]string, size)}
+}
+
+// Add records a line, dropping the oldest once the buffer is full
+func (b *LogBuffer) Add(line string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.lines) == 0 {
+		return
+	}
+	b.lines[b.next] = line
+	b.next = (b.next + 1) % len(b.lines)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// Last returns up to n of the most recent lines, oldest first
+func (b *LogBuffer) Last(n int) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stored := b.next
+	if b.full {
+		stored = len(b.lines)
+	}
+	if n > stored {
+		n = stored
+	}
+	out := make([]string, 0, n)
+	for i := b.next - n; i < b.next; i++ {
+		out = append(out, b.lines[(i+len(b.lines))%len(b.lines)])
+	}
+	return out
+}
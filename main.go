@@ -1,13 +1,22 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 )
 
@@ -15,22 +24,130 @@ import (
 type Config struct {
 	TelegramToken     string
 	TelegramAPI       string
-	ElementWait       time.Duration
-	PageLoadWait      time.Duration
+	ElementWait       time.Duration // How long the form and success elements may take to appear
+	PageLoadWait      time.Duration // Navigation timeout for the event page
+	FieldDelay        time.Duration // Pause after filling each form field
+	SubmitWait        time.Duration // Pause after submitting before checking the result
+	Humanize          bool          // Type fields key by key and randomize the pauses
+	HumanizeDelay     DurationRange // Pause after each field with Humanize
+	KeystrokeDelay    DurationRange // Pause between keystrokes with Humanize
+	UserAgents        []UserAgent   // Rotated per browser context; --user-agents replaces the built-in list
+	Viewports         []Viewport    // Window sizes for user agents without a paired size
+	Device            string        // Playwright device to emulate instead of a desktop identity
+	Browser           string        // Playwright engine: chromium, firefox or webkit
+	Country           string        // Only use proxies tagged with this country code
+	AllowedChats      []int64       // Chats the bot answers besides AdminChat; with neither, it answers everyone
+	AdminChat         int64         // Chat that may /allow and /deny others at runtime; 0 for none
+	ProxyMaxFailures  int           // Consecutive proxy errors before a proxy leaves rotation; 0 never removes
 	RegistrationRetry int
+	RetryBaseDelay    time.Duration // Retry n waits RetryBaseDelay * 2^n with ±20% jitter
+	RetryMaxDelay     time.Duration // Upper bound on the retry wait before jitter
 	MaxWorkers        int
+	MaxGlobalBrowsers int     // Chromium instances allowed across all campaigns; 0 is unlimited
+	RateLimit         float64 // Registrations started per second across all campaigns; 0 is unlimited
+	ProxyRateLimit    float64 // Attempts per second through each proxy; 0 is unlimited
+	EventRateLimit    float64 // Attempts per second against each event host; 0 is unlimited
+	StrictURLs        bool    // Abort when the pre-flight check finds unreachable event URLs
+	Shuffle           bool    // Queue email×event tasks in random order
+	Interleave        bool    // Queue tasks so events take turns instead of one event at a time
+	Seed              int64   // Seeds every random choice so a run can be repeated; 0 seeds from the clock
+	ProxySessionScope string
+	PinProxy          bool
+	FailFast          bool
+	FailFastStatuses  []string
+	ExtraHeaders      map[string]string
+	BlockResources    []string // Playwright resource types aborted instead of loaded
+	StartJitter       time.Duration
+	RampDelay         time.Duration // Worker i waits i*RampDelay (plus jitter) before its first browser launch
+	LaunchFailures    int           // Consecutive browser launch failures that pause launches; 0 disables it
+	LaunchBackoff     time.Duration // How long launches pause after LaunchFailures failures
+	LaunchAbort       bool          // Abort the campaign instead of pausing launches
+	GeoProviders      []string
+	GeoEndpoint       string
+	GeoRatePerMinute  int
+	NavRetries        int
+	NavRetryDelay     time.Duration
+	API               *APIConfig
+	Captcha           CaptchaSolver // Set by --captcha-api-key; nil reports captchas as CAPTCHA_BLOCKED
+	GroupBy           string
+	OutputFormat      string
+	WebhookURL        string
+	AlertMode         string // How final failures reach Telegram: immediate, batched or off
+	ProxyStatsFile    string
+	StorageDir        string // Per event host cookies reused by later registrations; empty disables it
+	SuccessLedger     string // JSON Lines file of email+event pairs that already succeeded; empty disables it
+	ScreenshotDir     string // Where page and debug screenshots are written; empty is the working directory
+	FormAnchor        string
+	MaxMemory         uint64
+	EmailColumn       string
+	EmailRegex        *regexp.Regexp
+	OnlyDomains       []string // Email domains to keep, subdomains included; empty keeps all
+	ExcludeDomains    []string // Email domains to drop, subdomains included
+	VerifyMX          bool     // Drop emails whose domain has no MX record
+	IdleShutdown      time.Duration
+	ProgressInterval  time.Duration // Bot mode: push campaign progress this often; 0 disables it
+	ProgressStep      int           // Bot mode: also push progress every ProgressStep percent; 0 disables it
+	AlreadyRegistered []string      // Page text (lowercase) meaning the email is already signed up
+	Campaign          CampaignConfig
 }
 
 var config = Config{
-	TelegramToken:     "8144020899:AAFsc11elbxfhsYtzW-9vbStDZZ-TXhLxW0",
-	ElementWait:       10 * time.Second,
-	PageLoadWait:      15 * time.Second,
+	ElementWait:       5 * time.Second,
+	PageLoadWait:      60 * time.Second,
+	FieldDelay:        500 * time.Millisecond,
+	SubmitWait:        5 * time.Second,
+	HumanizeDelay:     DurationRange{Min: 400 * time.Millisecond, Max: 1500 * time.Millisecond},
+	KeystrokeDelay:    DurationRange{Min: 40 * time.Millisecond, Max: 180 * time.Millisecond},
 	RegistrationRetry: 3,
+	RetryBaseDelay:    2 * time.Second,
+	RetryMaxDelay:     30 * time.Second,
 	MaxWorkers:        20,
+	ProgressInterval:  15 * time.Minute,
+	ProgressStep:      25,
+	ProxySessionScope: "task",
+	Browser:           browserChromium,
+	FailFastStatuses:  []string{"UNKNOWN_STATUS"},
+	ExtraHeaders:      map[string]string{},
+	GeoProviders:      []string{"ip-api", "ipwho.is"},
+	GeoRatePerMinute:  40,
+	NavRetries:        2,
+	NavRetryDelay:     2 * time.Second,
+	ProxyMaxFailures:  3,
+	LaunchFailures:    5,
+	LaunchBackoff:     time.Minute,
+	ProxyStatsFile:    "proxy_stats.json",
+	StorageDir:        "storage_state",
+	SuccessLedger:     "succeeded.json",
+	ScreenshotDir:     "screenshots",
+	EmailColumn:       "email",
+	AlreadyRegistered: []string{
+		"already registered",
+		"already been registered",
+		"already signed up",
+		"duplicate registration",
+	},
+	OutputFormat: "both",
+	AlertMode:    alertBatched,
 }
 
-func init() {
-	config.TelegramAPI = fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", config.TelegramToken)
+// telegramTokenEnv is the environment variable holding the Telegram bot token
+const telegramTokenEnv = "TELEGRAM_BOT_TOKEN"
+
+// resolveTelegramToken picks the bot token from --telegram-token, then the
+// config file, then TELEGRAM_BOT_TOKEN, and derives the API URL from it. It
+// runs after the config file is applied, so config.TelegramToken holds the
+// file's token, if any.
+func resolveTelegramToken(flagToken string) {
+	if flagToken != "" {
+		config.TelegramToken = flagToken
+	} else if config.TelegramToken == "" {
+		config.TelegramToken = strings.TrimSpace(os.Getenv(telegramTokenEnv))
+	}
+
+	config.TelegramAPI = ""
+	if config.TelegramToken != "" {
+		config.TelegramAPI = fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", config.TelegramToken)
+	}
 }
 
 // ProxyConfig represents a proxy configuration
@@ -38,47 +155,163 @@ type ProxyConfig struct {
 	Server   string `json:"server"`
 	Username string `json:"username,omitempty"`
 	Password string `json:"password,omitempty"`
+	Country  string `json:"country,omitempty"` // Upper-case country code from the proxy line, if tagged
 }
 
 // RegistrationResult represents the result of a registration attempt
 type RegistrationResult struct {
-	Email     string    `json:"email"`
-	Event     string    `json:"event"`
-	Status    string    `json:"status"`
-	Attempt   int       `json:"attempt"`
-	Message   string    `json:"message"`
-	Timestamp time.Time `json:"timestamp"`
+	Email     string          `json:"email"`
+	Contact   Contact         `json:"contact"`
+	Event     string          `json:"event"`
+	EventURL  string          `json:"event_url,omitempty"` // Normalized event URL; identifies the event in the ledger and on --resume
+	Status    string          `json:"status"`
+	Category  FailureCategory `json:"category,omitempty"` // Why the last attempt failed; empty on success
+	Attempt   int             `json:"attempt"`
+	Message   string          `json:"message"`
+	Proxy     string          `json:"proxy,omitempty"`
+	Attempts  []AttemptRecord `json:"attempts,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// AttemptRecord captures the outcome of a single attempt within a task
+type AttemptRecord struct {
+	Attempt    int             `json:"attempt"`
+	Proxy      string          `json:"proxy,omitempty"`
+	Success    bool            `json:"success"`
+	Category   FailureCategory `json:"category,omitempty"`
+	Message    string          `json:"message"`
+	Screenshot string          `json:"screenshot,omitempty"` // Debug screenshot of an unconfirmed attempt
+	Timestamp  time.Time       `json:"timestamp"`
 }
 
-// Logger provides structured logging
+// Logger provides structured logging as "[LEVEL] msg" text lines or, with
+// --log-format=json, one JSON object per line
 type Logger struct {
-	verbose bool
+	verbose *atomic.Bool // Shared with loggers created by With, so SetVerbose reaches them
+	json    *log.Logger  // Set in JSON mode; writes without the log package's prefix
+	fields  []logField
+}
+
+// logField is a key/value attached to every line of a Logger created by With
+type logField struct {
+	key   string
+	value interface{}
 }
 
 func NewLogger(verbose bool) *Logger {
-	return &Logger{verbose: verbose}
+	l := &Logger{verbose: &atomic.Bool{}}
+	l.verbose.Store(verbose)
+	return l
+}
+
+// NewLoggerWithFormat creates a logger writing "text" (the default) or "json" lines
+func NewLoggerWithFormat(verbose bool, format string) (*Logger, error) {
+	switch format {
+	case "", "text":
+		return NewLogger(verbose), nil
+	case "json":
+		l := NewLogger(verbose)
+		l.json = log.New(log.Writer(), "", 0)
+		return l, nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q (expected text or json)", format)
+	}
+}
+
+// With returns a logger that adds key=value as a structured field to every line
+func (l *Logger) With(key string, value interface{}) *Logger {
+	child := *l
+	child.fields = append(append([]logField(nil), l.fields...), logField{key, value})
+	return &child
+}
+
+// emit writes one log line in the logger's format
+func (l *Logger) emit(level, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	recentLogs.Add(fmt.Sprintf("%s [%s] %s", time.Now().Format("15:04:05"), level, msg))
+	if l.json == nil {
+		log.Printf("[%s] %s", level, msg)
+		return
+	}
+
+	entry := make(map[string]interface{}, len(l.fields)+3)
+	for _, f := range l.fields {
+		entry[f.key] = f.value
+	}
+	entry["level"] = strings.ToLower(level)
+	entry["time"] = time.Now().Format(time.RFC3339Nano)
+	entry["msg"] = msg
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		data, _ = json.Marshal(map[string]string{"level": "error", "msg": fmt.Sprintf("failed to encode log entry: %v", err)})
+	}
+	l.json.Println(string(data))
 }
 
 func (l *Logger) Info(format string, args ...interface{}) {
-	log.Printf("[INFO] "+format, args...)
+	l.emit("INFO", format, args...)
 }
 
 func (l *Logger) Debug(format string, args ...interface{}) {
-	if l.verbose {
-		log.Printf("[DEBUG] "+format, args...)
+	if l.verbose.Load() {
+		l.emit("DEBUG", format, args...)
 	}
 }
 
+// SetVerbose turns debug lines on or off for this logger and every logger derived from it
+func (l *Logger) SetVerbose(verbose bool) {
+	l.verbose.Store(verbose)
+}
+
+// ToggleVerbose flips debug logging and returns the new setting
+func (l *Logger) ToggleVerbose() bool {
+	for {
+		old := l.verbose.Load()
+		if l.verbose.CompareAndSwap(old, !old) {
+			return !old
+		}
+	}
+}
+
+// Verbose reports whether debug lines are written
+func (l *Logger) Verbose() bool {
+	return l.verbose.Load()
+}
+
 func (l *Logger) Error(format string, args ...interface{}) {
-	log.Printf("[ERROR] "+format, args...)
+	l.emit("ERROR", format, args...)
 }
 
 func (l *Logger) Warning(format string, args ...interface{}) {
-	log.Printf("[WARN] "+format, args...)
+	l.emit("WARN", format, args...)
+}
+
+// headerFlag collects repeatable key=value HTTP header flags
+type headerFlag map[string]string
+
+func (h headerFlag) String() string {
+	var pairs []string
+	for k, v := range h {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (h headerFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return fmt.Errorf("invalid header %q (expected key=value)", value)
+	}
+	h[key] = strings.TrimSpace(val)
+	return nil
 }
 
 func main() {
 	// Command-line flags
+	showVersion := flag.Bool("version", false, "Print version information and exit")
+	configPath := flag.String("config", "", "JSON or YAML config file; flags given explicitly override its values")
 	botMode := flag.Bool("bot", false, "Run in Telegram bot mode (interactive)")
 	firstName := flag.String("first-name", "", "Registration first name (REQUIRED for CLI mode)")
 	lastName := flag.String("last-name", "", "Registration last name (REQUIRED for CLI mode)")
@@ -87,18 +320,365 @@ func main() {
 	eventsFile := flag.String("events", "list.txt", "Event URLs file path")
 	proxiesFile := flag.String("proxies", "proxies.txt", "Proxy file path")
 	workers := flag.Int("workers", config.MaxWorkers, "Max concurrent workers")
+	retries := flag.Int("retries", config.RegistrationRetry, fmt.Sprintf("Attempts per registration (%d-%d)", minRegistrationRetries, maxRegistrationRetries))
+	autoCapWorkers := flag.Bool("auto-cap-workers-to-proxies", false, "Clamp workers to --workers-per-proxy times the proxy count")
+	workersPerProxy := flag.Int("workers-per-proxy", 1, "Workers allowed to share one proxy")
 	headless := flag.Bool("headless", true, "Run browser in headless mode")
 	windowMode := flag.Bool("window", false, "Show browser window")
-	verbose := flag.Bool("verbose", false, "Enable debug logging")
+	verbose := flag.Bool("verbose", false, "Enable debug logging (toggle at runtime with SIGUSR1 or the bot's /verbose)")
+	logFormat := flag.String("log-format", "text", "Log output format: text or json (one object per line)")
 	telegram := flag.String("telegram", "", "Telegram chat ID for notifications")
-	debug := flag.Bool("debug", false, "Run in debug mode (test IP info and fake logs)")
+	telegramToken := flag.String("telegram-token", "", "Telegram bot token (default: telegram_token from --config, then $"+telegramTokenEnv+")")
+	allowedChats := flag.String("allowed-chats", "", "Bot mode: comma-separated chat IDs allowed to use the bot (default: $"+allowedChatsEnv+")")
+	adminChat := flag.String("admin-chat", "", "Bot mode: chat ID that is always allowed and can /allow and /deny others (default: $"+adminChatEnv+")")
+	geoProviderList := flag.String("geo-providers", strings.Join(config.GeoProviders, ","), "Geo lookup providers in fallback order (ip-api, ipwho.is)")
+	geoEndpoint := flag.String("geo-endpoint", "", "Custom ip-api compatible endpoint, {ip} is replaced with the address")
+	geoRate := flag.Int("geo-rate", config.GeoRatePerMinute, "Max geo lookups per minute per provider")
+	navRetries := flag.Int("nav-retries", config.NavRetries, "Quick retries for transient navigation errors within one attempt")
+	apiMode := flag.Bool("api-mode", false, "Submit registrations as direct HTTP requests instead of a browser")
+	apiConfigFile := flag.String("api-config", "api.json", "API mode config file (endpoint, method, field mapping)")
+	captchaAPIKey := flag.String("captcha-api-key", "", "API key for solving hCaptcha/reCAPTCHA (without it captcha pages are reported as CAPTCHA_BLOCKED)")
+	captchaProvider := flag.String("captcha-provider", "2captcha", "Captcha solving service used with --captcha-api-key")
+	browsersPath := flag.String("browsers-path", "", "Persistent directory for Playwright browser downloads (sets PLAYWRIGHT_BROWSERS_PATH)")
+	skipInstall := flag.Bool("skip-install", false, "Don't check or download Playwright browsers at startup (they're pre-installed)")
+	launchFailures := flag.Int("launch-failure-limit", config.LaunchFailures, "Pause browser launches after this many consecutive launch failures across workers (0 = never)")
+	launchBackoff := flag.Duration("launch-backoff", config.LaunchBackoff, "How long browser launches pause after --launch-failure-limit failures")
+	launchAbort := flag.Bool("abort-on-launch-failure", false, "Abort the campaign instead of pausing when --launch-failure-limit is reached")
+	rampDelay := flag.Duration("ramp-delay", 0, "Stagger browser launches: worker i waits i times this (plus jitter) before its first launch (e.g. 500ms)")
+	startJitter := flag.Duration("start-jitter", 0, "Max random delay before each task's first navigation (e.g. 5s)")
+	pageLoadWait := flag.Duration("page-load-wait", config.PageLoadWait, "Timeout for loading an event page")
+	elementWait := flag.Duration("element-wait", config.ElementWait, "How long to wait for the form to render and for the success message")
+	fieldDelay := flag.Duration("field-delay", config.FieldDelay, "Pause after filling each form field")
+	submitWait := flag.Duration("submit-wait", config.SubmitWait, "Pause after submitting before checking the result")
+	userAgentsFile := flag.String("user-agents", "", "File of user agents to rotate per context, one per line with optional |WIDTHxHEIGHT (default: built-in list)")
+	viewports := flag.String("viewports", "", "Comma-separated window sizes picked at random per context, e.g. 1920x1080,1366x768 (default: common desktop sizes)")
+	proxyMaxFailures := flag.Int("proxy-max-failures", config.ProxyMaxFailures, "Consecutive proxy errors before a proxy is taken out of rotation (0 = never)")
+	country := flag.String("country", "", "Only use proxies tagged with this country code (e.g. US; tag proxies as host:port:user:pass:US)")
+	device := flag.String("device", "", "Emulate a Playwright device instead of a desktop browser, e.g. \"iPhone 13\"")
+	browserEngine := flag.String("browser", config.Browser, "Browser engine: chromium, firefox or webkit")
+	humanize := flag.Bool("humanize", false, "Type into fields key by key and randomize pauses to look less like a bot (slower)")
+	humanizeDelay := flag.String("humanize-delay", config.HumanizeDelay.String(), "With --humanize, random pause range after each field")
+	keystrokeDelay := flag.String("keystroke-delay", config.KeystrokeDelay.String(), "With --humanize, random pause range between keystrokes")
+	profileName := flag.String("profile", defaultTemplateName, "Form template for events without a |template suffix ("+strings.Join(formTemplateNames(), ", ")+" or one from --selectors)")
+	selectorsFile := flag.String("selectors", "selectors.json", "JSON file with custom form templates, referenced from list.txt as url|template")
+	debug := flag.Bool("debug", false, "Run diagnostics (IP info, proxies, event URLs and their forms) without registering")
+	demo := flag.Bool("demo", false, "With --debug, also print simulated registration logs")
+	testOne := flag.String("test-one", "", "Run one real registration with verbose logging and print the detailed result: \"<event URL> <email>\" (add --window to watch it)")
+	dryRun := flag.Bool("dry-run", false, "Load each event's form and check the selectors match, without filling or submitting")
+	failFast := flag.Bool("fail-fast", false, "Abort the campaign on the first result with a fail-fast status")
+	alreadyRegistered := flag.String("already-registered-keywords", strings.Join(config.AlreadyRegistered, ","), "Comma-separated page phrases that mean the email is already registered")
+	failFastOn := flag.String("fail-fast-on", strings.Join(config.FailFastStatuses, ","), "Comma-separated result statuses that trigger --fail-fast")
+	blockResourceList := flag.String("block-resources", "", "Comma-separated resource types not to load, e.g. image,media,font; add stylesheet only if forms don't rely on CSS to show fields")
+	flag.Var(headerFlag(config.ExtraHeaders), "extra-headers", "Extra HTTP header as key=value (repeatable)")
+	proxySessionScope := flag.String("proxy-session-scope", config.ProxySessionScope, "Scope of the {session} proxy placeholder: task (new per attempt) or worker")
+	pinProxy := flag.Bool("pin-proxy", false, "Keep the same proxy for every retry of a task instead of rotating")
+	outputFormat := flag.String("output-format", config.OutputFormat, "Results file format: json, csv or both")
+	webhook := flag.String("webhook", "", "URL to POST the campaign summary and results to when finished")
+	alertMode := flag.String("alert-mode", config.AlertMode, "Telegram failure alerts: batched (the default, a digest every 30s or 20 failures), immediate (one message per failure, the old default) or off")
+	groupBy := flag.String("group-by", "", "Also write grouped result files: email (results/<email>.json)")
+	proxyStatsFile := flag.String("proxy-stats-file", config.ProxyStatsFile, "File persisting per-proxy success stats across runs (empty to disable)")
+	screenshotDir := flag.String("screenshot-dir", config.ScreenshotDir, "Directory for page and debug screenshots (empty for the working directory)")
+	successLedger := flag.String("success-ledger", config.SuccessLedger, "File recording succeeded email+event pairs; those are skipped on later runs (empty to disable)")
+	noStorageReuse := flag.Bool("no-storage-reuse", false, "Start every browser context without cookies instead of reusing the consent cookies saved after a success on the same event host")
+	resume := flag.String("resume", "", "Results stream (.jsonl) of an interrupted run; tasks that succeeded or were already registered in it are skipped, the rest run again")
+	ignoreLedger := flag.Bool("ignore-ledger", false, "Run every task even if the success ledger says it already succeeded")
+	showProxyStats := flag.Bool("proxy-stats", false, "Print the proxy reliability ranking and exit")
+	formAnchor := flag.String("form-anchor", "", "Selector that must be present after navigation before filling (default: profile's)")
+	maxMemory := flag.String("max-memory", "", "Pause new jobs while browser memory exceeds this size (e.g. 4G)")
+	emailRegex := flag.String("email-regex", "", "Custom email extraction regex, optionally with a (?P<email>...) group")
+	shutdownGrace := flag.Duration("shutdown-grace", 30*time.Second, "On SIGINT/SIGTERM, how long in-flight tasks may finish before being cancelled")
+	metricsAddr := flag.String("metrics-addr", "", "Serve /healthz and /metrics JSON on this address (e.g. :9090)")
+	prometheusExport := flag.Bool("prometheus", false, "Also serve Prometheus metrics at /metrics/prometheus on --metrics-addr (needs -tags prometheus)")
+	strictURLs := flag.Bool("strict-urls", false, "Abort if any event URL returns 4xx/5xx or is unreachable before the campaign starts")
+	shuffle := flag.Bool("shuffle", false, "Run email×event tasks in random order to spread load across events and proxies")
+	interleave := flag.Bool("interleave", false, "Alternate between events (email 1 on every event, then email 2, ...) instead of finishing one event first")
+	seed := flag.Int64("seed", 0, "Seed for task shuffle, jitter and user agent/viewport picks, so a run can be repeated with the same inputs (0 = random)")
+	rateLimit := flag.Float64("rate-limit", 0, "Max registrations started per second across all workers and bot campaigns (0 = unlimited)")
+	eventRateLimit := flag.Float64("event-rate-limit", 0, "Max registration attempts per second against each event host; a template's rate_limit overrides it (0 = unlimited)")
+	proxyRateLimit := flag.Float64("proxy-rate-limit", 0, "Max registration attempts per second through each proxy (0 = unlimited)")
+	maxBrowsers := flag.Int("max-browsers", 0, "Max Chromium instances running at once across all workers and bot campaigns, shared first-come (0 = unlimited)")
+	idleShutdown := flag.Duration("idle-shutdown", 0, "Bot mode: exit after this long with no messages or running campaign (e.g. 30m)")
+	progressInterval := flag.Duration("progress-interval", config.ProgressInterval, "Bot mode: send campaign progress to the chat this often (0 = off)")
+	progressStep := flag.Int("progress-step", config.ProgressStep, "Bot mode: also send campaign progress every N% completed (0 = off)")
+	onlyDomains := flag.String("only-domains", "", "Comma-separated email domains to keep from --emails, subdomains included (e.g. example.com,example.org)")
+	excludeDomains := flag.String("exclude-domains", "", "Comma-separated email domains to drop from --emails, subdomains included")
+	verifyMX := flag.Bool("verify-mx", false, "Look up each email domain's MX record (once per domain) and drop addresses whose domain takes no mail")
+	emailColumn := flag.String("email-column", config.EmailColumn, "Column holding addresses when --emails is a .csv file")
+	registrantsFile := flag.String("registrants", "", "CSV of registrants (email, first_name, last_name, organization) used instead of --emails")
+	registrantsStrict := flag.Bool("registrants-strict", false, "Fail on any invalid registrant row, listing all errors with line numbers")
 
 	flag.Parse()
 
-	logger := NewLogger(*verbose)
+	if *showVersion {
+		fmt.Println(versionString())
+		return
+	}
+
+	logger, err := NewLoggerWithFormat(*verbose, *logFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --log-format: %v\n", err)
+		os.Exit(1)
+	}
+	watchVerboseSignal(logger)
+
+	if *configPath != "" {
+		fileConfig, err := LoadConfigFile(*configPath)
+		if err != nil {
+			logger.Error("%v", err)
+			os.Exit(1)
+		}
+		applyConfigFile(fileConfig)
+		logger.Info("Loaded config from %s", *configPath)
+	}
+
+	resolveTelegramToken(*telegramToken)
+	if err := resolveChatAccess(*allowedChats, *adminChat); err != nil {
+		logger.Error("Invalid bot access settings: %v", err)
+		os.Exit(1)
+	}
+
+	config.ProxyStatsFile = *proxyStatsFile
+	config.SuccessLedger = *successLedger
+	config.ScreenshotDir = *screenshotDir
+	if *noStorageReuse {
+		config.StorageDir = ""
+	}
+	config.FormAnchor = *formAnchor
+	config.IdleShutdown = *idleShutdown
+	if *progressInterval < 0 || *progressStep < 0 || *progressStep > 100 {
+		logger.Error("Invalid --progress-interval/--progress-step (expected a duration of 0 or more and a percentage of 0-100)")
+		os.Exit(1)
+	}
+	config.ProgressInterval = *progressInterval
+	config.ProgressStep = *progressStep
+	config.MaxGlobalBrowsers = *maxBrowsers
+	config.StrictURLs = *strictURLs
+	config.Shuffle = *shuffle
+	config.Interleave = *interleave
+	config.Seed = seedRandom(*seed)
+	logger.Debug("Random seed: %d", config.Seed)
+	browserSlots = NewBrowserLimiter(config.MaxGlobalBrowsers)
+	config.RateLimit = *rateLimit
+	config.ProxyRateLimit = *proxyRateLimit
+	registrationRate = NewRateLimiter(config.RateLimit)
+	config.EventRateLimit = *eventRateLimit
+	config.EmailColumn = *emailColumn
+	config.WebhookURL = *webhook
+	switch *alertMode {
+	case alertImmediate, alertBatched, alertOff:
+		config.AlertMode = *alertMode
+	default:
+		logger.Error("Invalid --alert-mode %q (expected immediate, batched or off)", *alertMode)
+		os.Exit(1)
+	}
+	if config.AlertMode == alertBatched && config.TelegramAPI != "" {
+		logger.Info("Telegram failure alerts are batched into a digest every %v or %d failures (--alert-mode immediate for one per failure)", alertDigestInterval, alertDigestSize)
+	}
+
+	if *emailRegex != "" {
+		re, err := compileEmailRegex(*emailRegex)
+		if err != nil {
+			logger.Error("Invalid --email-regex: %v", err)
+			os.Exit(1)
+		}
+		config.EmailRegex = re
+	}
+
+	config.VerifyMX = *verifyMX
+	if config.OnlyDomains, err = parseDomainList(*onlyDomains); err != nil {
+		logger.Error("Invalid --only-domains: %v", err)
+		os.Exit(1)
+	}
+	if config.ExcludeDomains, err = parseDomainList(*excludeDomains); err != nil {
+		logger.Error("Invalid --exclude-domains: %v", err)
+		os.Exit(1)
+	}
+
+	if *maxMemory != "" {
+		limit, err := parseByteSize(*maxMemory)
+		if err != nil {
+			logger.Error("Invalid --max-memory: %v", err)
+			os.Exit(1)
+		}
+		config.MaxMemory = limit
+	}
+
+	if *retries < minRegistrationRetries || *retries > maxRegistrationRetries {
+		logger.Error("Invalid --retries %d (expected %d-%d)", *retries, minRegistrationRetries, maxRegistrationRetries)
+		os.Exit(1)
+	}
+	config.RegistrationRetry = *retries
+
+	if *showProxyStats {
+		printProxyStats(logger)
+		return
+	}
+
+	if *proxySessionScope != "task" && *proxySessionScope != "worker" {
+		logger.Error("Invalid --proxy-session-scope %q (expected task or worker)", *proxySessionScope)
+		os.Exit(1)
+	}
+	config.ProxySessionScope = *proxySessionScope
+	config.PinProxy = *pinProxy
+
+	if *groupBy != "" && *groupBy != "email" {
+		logger.Error("Invalid --group-by %q (expected email)", *groupBy)
+		os.Exit(1)
+	}
+	config.GroupBy = *groupBy
+
+	switch *outputFormat {
+	case "json", "csv", "both":
+		config.OutputFormat = *outputFormat
+	default:
+		logger.Error("Invalid --output-format %q (expected json, csv or both)", *outputFormat)
+		os.Exit(1)
+	}
+
+	if *apiMode {
+		apiConfig, err := loadAPIConfig(*apiConfigFile)
+		if err != nil {
+			logger.Error("Failed to load API config: %v", err)
+			os.Exit(1)
+		}
+		config.API = apiConfig
+		logger.Info("API mode enabled: %s %s", apiConfig.Method, apiConfig.Endpoint)
+	}
+
+	if *captchaAPIKey != "" {
+		solver, err := newCaptchaSolver(*captchaProvider, *captchaAPIKey)
+		if err != nil {
+			logger.Error("Invalid --captcha-provider: %v", err)
+			os.Exit(1)
+		}
+		config.Captcha = solver
+		logger.Info("Captcha solving enabled via %s", *captchaProvider)
+	}
+
+	if *browsersPath != "" {
+		if err := setBrowsersPath(*browsersPath); err != nil {
+			logger.Error("Invalid --browsers-path: %v", err)
+			os.Exit(1)
+		}
+		logger.Info("Playwright browsers path: %s", *browsersPath)
+	}
+
+	// Check the browser install once, before any worker needs it
+	needsBrowser := config.API == nil || config.API.FallbackToBrowser
+	if needsBrowser && *skipInstall {
+		logger.Debug("Skipping the Playwright install check (--skip-install)")
+	}
+	if err := prepareBrowser(*browserEngine, needsBrowser && !*skipInstall); err != nil {
+		logger.Error("%v", err)
+		os.Exit(1)
+	}
+
+	if err := loadFormTemplates(*selectorsFile, logger); err != nil {
+		logger.Error("Failed to load form templates: %v", err)
+		os.Exit(1)
+	}
+
+	profile, ok := getFormTemplate(*profileName)
+	if !ok {
+		logger.Error("Unknown --profile %q (available: %s)", *profileName, strings.Join(formTemplateNames(), ", "))
+		os.Exit(1)
+	}
+	config.StartJitter = *startJitter
+	config.RampDelay = *rampDelay
+	config.LaunchFailures = *launchFailures
+	config.LaunchBackoff = *launchBackoff
+	config.LaunchAbort = *launchAbort
+	launchBreaker = NewLaunchBreaker(config.LaunchFailures, config.LaunchBackoff, config.LaunchAbort)
+	for _, wait := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"page-load-wait", *pageLoadWait},
+		{"element-wait", *elementWait},
+		{"field-delay", *fieldDelay},
+		{"submit-wait", *submitWait},
+	} {
+		if wait.value < 0 {
+			logger.Error("Invalid --%s %s (expected 0 or more)", wait.name, wait.value)
+			os.Exit(1)
+		}
+	}
+	config.PageLoadWait = *pageLoadWait
+	config.ElementWait = *elementWait
+	config.FieldDelay = *fieldDelay
+	config.SubmitWait = *submitWait
+	config.Humanize = *humanize
+	config.UserAgents = defaultUserAgents
+	config.Viewports = defaultViewports
+	config.Device = *device
+	if config.Device != "" && needsBrowser {
+		if err := checkDevice(config.Device); err != nil {
+			logger.Error("%v", err)
+			os.Exit(1)
+		}
+	}
+	config.Country = strings.ToUpper(strings.TrimSpace(*country))
+	config.ProxyMaxFailures = *proxyMaxFailures
+	if *viewports != "" {
+		if config.Viewports, err = parseViewports(*viewports); err != nil {
+			logger.Error("Invalid --viewports: %v", err)
+			os.Exit(1)
+		}
+	}
+	if *userAgentsFile != "" {
+		if config.UserAgents, err = readUserAgents(*userAgentsFile, logger); err != nil {
+			logger.Error("Invalid --user-agents: %v", err)
+			os.Exit(1)
+		}
+	}
+	if config.HumanizeDelay, err = parseDurationRange(*humanizeDelay); err != nil {
+		logger.Error("Invalid --humanize-delay: %v", err)
+		os.Exit(1)
+	}
+	if config.KeystrokeDelay, err = parseDurationRange(*keystrokeDelay); err != nil {
+		logger.Error("Invalid --keystroke-delay: %v", err)
+		os.Exit(1)
+	}
+	if *navRetries < 0 {
+		logger.Error("Invalid --nav-retries %d (expected 0 or more)", *navRetries)
+		os.Exit(1)
+	}
+	config.NavRetries = *navRetries
+	config.GeoProviders = splitList(*geoProviderList)
+	config.GeoEndpoint = *geoEndpoint
+	config.GeoRatePerMinute = *geoRate
+	config.FailFast = *failFast
+	config.FailFastStatuses = splitList(*failFastOn)
+	config.AlreadyRegistered = splitList(strings.ToLower(*alreadyRegistered))
+	if config.BlockResources, err = parseResourceTypes(*blockResourceList); err != nil {
+		logger.Error("Invalid --block-resources: %v", err)
+		os.Exit(1)
+	}
+
+	if *prometheusExport {
+		if *metricsAddr == "" {
+			logger.Error("--prometheus requires --metrics-addr")
+			os.Exit(1)
+		}
+		if err := enablePrometheus(campaignMetrics); err != nil {
+			logger.Error("Cannot enable --prometheus: %v", err)
+			os.Exit(1)
+		}
+	}
+
+	if *metricsAddr != "" {
+		if _, err := startMetricsServer(*metricsAddr, campaignMetrics, logger); err != nil {
+			logger.Error("Invalid --metrics-addr: %v", err)
+			os.Exit(1)
+		}
+	}
 
 	// Bot mode - interactive control via Telegram
 	if *botMode {
+		if config.TelegramToken == "" {
+			logger.Error("Bot mode needs a Telegram bot token: set %s or pass --telegram-token", telegramTokenEnv)
+			os.Exit(1)
+		}
 		logger.Info("Starting in Telegram Bot mode...")
 		logger.Info("Send /start to your bot to begin")
 		RunBotMode(logger)
@@ -106,8 +686,8 @@ func main() {
 	}
 
 	// CLI mode - requires arguments
-	if *firstName == "" || *lastName == "" || *organization == "" {
-		fmt.Println("Error: --first-name, --last-name, and --organization are required")
+	if *registrantsFile == "" && (*firstName == "" || *lastName == "" || *organization == "") {
+		fmt.Println("Error: --first-name, --last-name, and --organization are required (or use --registrants)")
 		fmt.Println("")
 		fmt.Println("Or use --bot flag to run in interactive Telegram bot mode:")
 		fmt.Println("  go run . --bot")
@@ -120,7 +700,9 @@ func main() {
 	logger.Info("Starting Event Registration Automation")
 
 	// Test Telegram connection if chat ID provided
-	if *telegram != "" {
+	if *telegram != "" && config.TelegramToken == "" {
+		logger.Warning("Telegram notifications disabled (--telegram given but no bot token; set %s)", telegramTokenEnv)
+	} else if *telegram != "" {
 		logger.Info("Telegram notifications enabled (Chat ID: %s)", *telegram)
 		testTelegramConnection(*telegram, logger)
 	} else {
@@ -129,15 +711,50 @@ func main() {
 
 	// Debug mode
 	if *debug {
-		runDebugMode(logger, *proxiesFile, *eventsFile)
+		runDebugMode(logger, *proxiesFile, *eventsFile, profile, *demo)
+		return
+	}
+
+	// One end-to-end registration, the quickest way to validate a template
+	if *testOne != "" {
+		eventURL, email, err := parseTestTarget(*testOne)
+		if err != nil {
+			logger.Error("Invalid --test-one: %v", err)
+			os.Exit(1)
+		}
+		proxies, err := readProxies(*proxiesFile, logger)
+		if err != nil {
+			logger.Warning("Failed to read proxies, testing without one: %v", err)
+		}
+		testLogger, _ := NewLoggerWithFormat(true, *logFormat)
+		contact := Contact{Email: email, FirstName: *firstName, LastName: *lastName, Organization: *organization}
+		result := runTestOne(context.Background(), eventURL, contact, profile, !*windowMode && *headless, config.RegistrationRetry, proxies, testLogger)
+
+		logger.Info("=== TEST RESULT ===")
+		for _, line := range formatTestReport(result) {
+			logger.Info("%s", line)
+		}
+		if result.Status != "SUCCESS" {
+			os.Exit(1)
+		}
 		return
 	}
 
 	// Load configuration files
-	emails, err := readEmails(*emailsFile, logger)
-	if err != nil {
-		logger.Error("Failed to read emails: %v", err)
-		os.Exit(1)
+	var contacts []Contact
+	if *registrantsFile != "" {
+		defaults := Contact{FirstName: *firstName, LastName: *lastName, Organization: *organization}
+		contacts, err = readRegistrants(*registrantsFile, defaults, *registrantsStrict, logger)
+		if err != nil {
+			logger.Error("Failed to read registrants: %v", err)
+			os.Exit(1)
+		}
+	} else {
+		contacts, err = readContacts(*emailsFile, logger)
+		if err != nil {
+			logger.Error("Failed to read emails: %v", err)
+			os.Exit(1)
+		}
 	}
 
 	eventURLs, err := readEventURLs(*eventsFile, logger)
@@ -145,31 +762,114 @@ func main() {
 		logger.Error("Failed to read event URLs: %v", err)
 		os.Exit(1)
 	}
+	if err := validateEventTemplates(eventURLs); err != nil {
+		logger.Error("Invalid event list: %v", err)
+		os.Exit(1)
+	}
+
+	logger.Info("Checking %d event URLs...", len(eventURLs))
+	if unreachable := unreachableEventURLs(checkEventURLs(eventURLs, 10*time.Second)); len(unreachable) > 0 {
+		for _, check := range unreachable {
+			logger.Warning("Event URL unreachable (%s): %s", check.Problem(), check.URL)
+		}
+		if config.StrictURLs {
+			logger.Error("%d of %d event URLs unreachable (--strict-urls)", len(unreachable), len(eventURLs))
+			os.Exit(1)
+		}
+	}
 
 	proxies, err := readProxies(*proxiesFile, logger)
 	if err != nil {
 		logger.Warning("Failed to read proxies: %v", err)
 		proxies = []ProxyConfig{} // Continue without proxies
 	}
+	if proxies, err = filterProxiesByCountry(proxies, config.Country); err != nil {
+		logger.Error("--country %s: %v", config.Country, err)
+		os.Exit(1)
+	}
+	if config.Country != "" {
+		logger.Info("Using %d proxies in %s", len(proxies), config.Country)
+	}
 
-	if len(emails) == 0 || len(eventURLs) == 0 {
+	if len(contacts) == 0 || len(eventURLs) == 0 {
 		logger.Error("Missing emails or event URLs")
 		os.Exit(1)
 	}
 
+	if maxForProxies := len(proxies) * *workersPerProxy; len(proxies) > 0 && *workers > maxForProxies {
+		if *autoCapWorkers {
+			capped := capWorkersToProxies(*workers, len(proxies), *workersPerProxy)
+			logger.Info("Capping workers from %d to %d (%d proxies × %d workers per proxy)", *workers, capped, len(proxies), *workersPerProxy)
+			*workers = capped
+		} else {
+			logger.Warning("%d workers share %d proxies (%d per proxy allowed) - consider --auto-cap-workers-to-proxies", *workers, len(proxies), *workersPerProxy)
+		}
+	}
+
+	// A dry run checks each event's form once, so a single contact is enough
+	if *dryRun && len(contacts) > 1 {
+		logger.Info("Dry run: checking %d event(s) with %s only", len(eventURLs), contacts[0].Email)
+		contacts = contacts[:1]
+	}
+
 	// Create orchestrator
 	orchestrator := NewRegistrationOrchestrator(
+		context.Background(),
 		*firstName,
 		*lastName,
 		*organization,
+		profile,
 		!*windowMode && *headless,
 		*workers,
 		*telegram,
 		logger,
 	)
+	if *dryRun {
+		orchestrator.SetDryRun(true)
+		orchestrator.SetWebhook("")
+	} else if config.SuccessLedger != "" {
+		ledger, err := LoadTaskLedger(config.SuccessLedger)
+		if err != nil {
+			logger.Error("%v", err)
+			os.Exit(1)
+		}
+		if *ignoreLedger && ledger.Len() > 0 {
+			logger.Info("Ignoring %d succeeded task(s) in %s", ledger.Len(), config.SuccessLedger)
+		}
+		orchestrator.SetLedger(ledger, !*ignoreLedger)
+	}
+	if *resume != "" && !*dryRun {
+		prior, err := readResultStream(*resume)
+		if err != nil && len(prior) == 0 {
+			logger.Error("Invalid --resume: %v", err)
+			os.Exit(1)
+		}
+		if err != nil {
+			logger.Warning("Stopped reading %s at a damaged line, resuming from the %d results before it: %v", *resume, len(prior), err)
+		}
+		orchestrator.SetResume(prior)
+	}
+
+	// First Ctrl-C drains the campaign so partial results are still saved; a second one stops at once
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		logger.Warning("Received %v - finishing in-flight tasks (up to %v) and saving results. Press Ctrl-C again to stop now", sig, *shutdownGrace)
+		orchestrator.Shutdown(*shutdownGrace)
+		<-signals
+		logger.Warning("Stopping in-flight tasks now")
+		orchestrator.Abort()
+	}()
 
 	// Run registration campaign
-	results := orchestrator.Run(eventURLs, emails, proxies)
+	campaignMetrics.Begin(orchestrator)
+	results := orchestrator.Run(eventURLs, contacts, proxies)
+	campaignMetrics.End(orchestrator)
+	signal.Stop(signals)
+	if !telegramOutbox.Drain(outboxDrain) {
+		logger.Warning("Gave up waiting for queued Telegram alerts after %v", outboxDrain)
+	}
 
 	if len(results) > 0 {
 		os.Exit(0)
@@ -182,85 +882,433 @@ type RegistrationOrchestrator struct {
 	firstName      string
 	lastName       string
 	organization   string
+	template       *FormTemplate
 	headless       bool
 	maxWorkers     int
+	retries        int
 	telegramChatID string
+	webhookURL     string
+	resultsPrefix  string // Result files are named <resultsPrefix>_<timestamp>
+	dryRun         bool
+	rateLimit      float64     // Effective registrations/sec allowed by the rate limits, 0 if unlimited
+	ledger         *TaskLedger // Records successes; nil disables the ledger
+	done           *TaskLedger // Tasks done by earlier runs, skipped instead of queued
 	logger         *Logger
+	newWorker      func(workerID int, proxyPool *ProxyPool) Worker
+	ctx            context.Context
+	cancel         context.CancelFunc
+	draining       atomic.Bool // Set by Shutdown: no new jobs start, in-flight ones finish
+	pauseMu        sync.Mutex
+	resume         chan struct{} // Non-nil while paused; closed by Resume
+	total          atomic.Int32
+	completed      atomic.Int32
+	successful     atomic.Int32
+	pace           *CompletionRate // When recent tasks completed, for the ETA
+	onResult       func(RegistrationResult)
+	pool           atomic.Pointer[workerPool] // Set while Run is running
 }
 
-func NewRegistrationOrchestrator(firstName, lastName, organization string, headless bool, maxWorkers int, telegramChatID string, logger *Logger) *RegistrationOrchestrator {
-	return &RegistrationOrchestrator{
+func NewRegistrationOrchestrator(ctx context.Context, firstName, lastName, organization string, template *FormTemplate, headless bool, maxWorkers int, telegramChatID string, logger *Logger) *RegistrationOrchestrator {
+	o := &RegistrationOrchestrator{
 		firstName:      firstName,
 		lastName:       lastName,
 		organization:   organization,
+		template:       template,
 		headless:       headless,
 		maxWorkers:     maxWorkers,
+		retries:        config.RegistrationRetry,
 		telegramChatID: telegramChatID,
+		webhookURL:     config.WebhookURL,
+		resultsPrefix:  "results",
 		logger:         logger,
+		pace:           NewCompletionRate(),
+	}
+	o.ctx, o.cancel = context.WithCancel(ctx)
+	o.newWorker = func(workerID int, proxyPool *ProxyPool) Worker {
+		worker := NewRegistrationWorker(workerID, proxyPool, o.headless, o.retries, o.telegramChatID, o.logger)
+		worker.dryRun = o.dryRun
+		// Workers added by SetWorkers mid-campaign launch at once; the
+		// --ramp-delay stagger is only for the initial pool
+		worker.ramped = workerID >= o.maxWorkers
+		return worker
+	}
+	return o
+}
+
+// SetRetries sets how many attempts each task gets
+func (o *RegistrationOrchestrator) SetRetries(retries int) {
+	o.retries = retries
+}
+
+// SetWebhook overrides the URL the campaign summary is POSTed to (empty disables it)
+func (o *RegistrationOrchestrator) SetWebhook(url string) {
+	o.webhookURL = url
+}
+
+// SetResultsPrefix changes the prefix of the result file names, keeping
+// concurrent campaigns from writing to the same files
+func (o *RegistrationOrchestrator) SetResultsPrefix(prefix string) {
+	o.resultsPrefix = prefix
+}
+
+// SetDryRun makes workers check the form fields of each event without
+// filling or submitting anything
+func (o *RegistrationOrchestrator) SetDryRun(dryRun bool) {
+	o.dryRun = dryRun
+}
+
+// SetWorkers changes how many workers the running campaign uses and returns
+// the previous count. Extra workers start on the remaining jobs; surplus ones
+// exit after their current job. It reports false if the campaign isn't
+// running or has no jobs left to hand out.
+func (o *RegistrationOrchestrator) SetWorkers(n int) (int, bool) {
+	pool := o.pool.Load()
+	if pool == nil {
+		return 0, false
+	}
+	previous, ok := pool.Resize(n)
+	if ok && previous != n {
+		o.logger.Info("👷 Workers: %d -> %d", previous, n)
+	}
+	return previous, ok
+}
+
+// Workers returns the number of workers the campaign is running with
+func (o *RegistrationOrchestrator) Workers() int {
+	if pool := o.pool.Load(); pool != nil {
+		return pool.Size()
+	}
+	return o.maxWorkers
+}
+
+// SetResultHandler calls fn with each result as it arrives, from the
+// goroutine collecting results, so callers can show a campaign's results
+// while it runs
+func (o *RegistrationOrchestrator) SetResultHandler(fn func(RegistrationResult)) {
+	o.onResult = fn
+}
+
+// SetLedger records successful tasks in ledger and, when skipDone is set,
+// skips the email+event pairs it already has
+func (o *RegistrationOrchestrator) SetLedger(ledger *TaskLedger, skipDone bool) {
+	o.ledger = ledger
+	if skipDone {
+		o.skipTasks(ledger)
 	}
 }
 
-func (o *RegistrationOrchestrator) Run(eventURLs, emails []string, proxies []ProxyConfig) []RegistrationResult {
-	totalTasks := len(eventURLs) * len(emails)
+// SetResume skips the tasks that reached a final outcome in prior, the
+// results of an interrupted run
+func (o *RegistrationOrchestrator) SetResume(prior []RegistrationResult) {
+	o.skipTasks(completedTasks(prior))
+}
+
+// skipTasks adds the pairs in done to the tasks Run skips
+func (o *RegistrationOrchestrator) skipTasks(done *TaskLedger) {
+	if o.done == nil {
+		o.done, _ = LoadTaskLedger("")
+	}
+	o.done.Merge(done)
+}
+
+// resolveContact fills blank contact fields from the campaign defaults
+func (o *RegistrationOrchestrator) resolveContact(c Contact) Contact {
+	if c.FirstName == "" {
+		c.FirstName = o.firstName
+	}
+	if c.LastName == "" {
+		c.LastName = o.lastName
+	}
+	if c.Organization == "" {
+		c.Organization = o.organization
+	}
+	return c
+}
+
+// Abort stops the campaign; queued jobs are drained without being executed
+// and in-flight tasks are cancelled
+func (o *RegistrationOrchestrator) Abort() {
+	o.cancel()
+}
+
+// Shutdown stops starting new jobs and lets in-flight tasks finish, cancelling
+// them if they are still running after grace
+func (o *RegistrationOrchestrator) Shutdown(grace time.Duration) {
+	if o.draining.Swap(true) {
+		return
+	}
+	time.AfterFunc(grace, o.cancel)
+}
+
+// Pause holds workers before their next job until Resume; in-flight tasks finish
+func (o *RegistrationOrchestrator) Pause() {
+	o.pauseMu.Lock()
+	defer o.pauseMu.Unlock()
+	if o.resume == nil {
+		o.resume = make(chan struct{})
+	}
+}
+
+// Resume releases workers held by Pause
+func (o *RegistrationOrchestrator) Resume() {
+	o.pauseMu.Lock()
+	defer o.pauseMu.Unlock()
+	if o.resume != nil {
+		close(o.resume)
+		o.resume = nil
+	}
+}
+
+// Paused reports whether the campaign is paused
+func (o *RegistrationOrchestrator) Paused() bool {
+	o.pauseMu.Lock()
+	defer o.pauseMu.Unlock()
+	return o.resume != nil
+}
+
+// waitWhilePaused blocks while paused, returning early if the campaign is cancelled
+func (o *RegistrationOrchestrator) waitWhilePaused() {
+	o.pauseMu.Lock()
+	resume := o.resume
+	o.pauseMu.Unlock()
+	if resume == nil {
+		return
+	}
+	select {
+	case <-resume:
+	case <-o.ctx.Done():
+	}
+}
+
+// Progress returns the completed, successful and total task counts of the running campaign
+func (o *RegistrationOrchestrator) Progress() (completed, successful, total int) {
+	return int(o.completed.Load()), int(o.successful.Load()), int(o.total.Load())
+}
+
+// ETA estimates the time left from the pace of recently completed tasks. It
+// reports false until enough tasks have completed to tell.
+func (o *RegistrationOrchestrator) ETA() (time.Duration, bool) {
+	completed, _, total := o.Progress()
+	return o.pace.ETA(total-completed, time.Now())
+}
+
+// aborted reports whether the campaign was aborted or its context cancelled
+func (o *RegistrationOrchestrator) aborted() bool {
+	return o.ctx.Err() != nil
+}
+
+// taskOrder maps the i-th queued task to its event and contact indexes:
+// all contacts for one event at a time by default, every event for one
+// contact at a time with interleave, or a random permutation with shuffle
+func taskOrder(events, contacts int, interleave, shuffle bool) func(i int) (event, contact int) {
+	var perm []int
+	if shuffle {
+		perm = random.Perm(events * contacts)
+	}
+	return func(i int) (int, int) {
+		if perm != nil {
+			i = perm[i]
+		}
+		if interleave {
+			return i % events, i / events
+		}
+		return i / contacts, i % contacts
+	}
+}
+
+// isFailFastStatus reports whether a result status should abort the campaign
+func isFailFastStatus(status string) bool {
+	for _, s := range config.FailFastStatuses {
+		if strings.EqualFold(s, status) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *RegistrationOrchestrator) Run(eventURLs []string, contacts []Contact, proxies []ProxyConfig) []RegistrationResult {
+	totalTasks := len(eventURLs) * len(contacts)
+	o.total.Store(int32(totalTasks))
 
 	o.logger.Info("Starting registration campaign:")
 	o.logger.Info("  Events: %d", len(eventURLs))
-	o.logger.Info("  Emails: %d", len(emails))
+	o.logger.Info("  Contacts: %d", len(contacts))
 	o.logger.Info("  Total tasks: %d", totalTasks)
 	o.logger.Info("  Workers: %d", o.maxWorkers)
+	o.logger.Info("  Retries: %d", o.retries)
 	o.logger.Info("  Headless: %v", o.headless)
+	o.logger.Info("  Template: %s", o.template.Name)
 	o.logger.Info("  Proxies: %d", len(proxies))
+	o.rateLimit = effectiveRateLimit(len(proxies))
+	if o.rateLimit > 0 {
+		o.logger.Info("  Rate limit: %s", formatRate(o.rateLimit))
+	}
+	if config.Shuffle {
+		o.logger.Info("  Order: shuffled (--seed %d to repeat)", config.Seed)
+	} else if config.Interleave {
+		o.logger.Info("  Order: events interleaved")
+	}
+
+	var proxyStats *ProxyStats
+	if config.ProxyStatsFile != "" && len(proxies) > 0 {
+		stats, err := OpenProxyStats(config.ProxyStatsFile)
+		if err != nil {
+			o.logger.Warning("Proxy stats unavailable: %v", err)
+		} else {
+			proxyStats = stats
+			proxies = append([]ProxyConfig(nil), proxies...)
+			proxyStats.SortProxies(proxies)
+			o.logger.Debug("Proxies ordered by historical success rate")
+		}
+	}
+
+	proxyPool := NewProxyPool(proxies)
 
 	startTime := time.Now()
+	defer o.cancel()
+
+	// Results are streamed to JSONL as they arrive and read back for the final files
+	baseName := fmt.Sprintf("%s_%s", o.resultsPrefix, startTime.Format("20060102_150405"))
+	stream, err := NewResultStream(baseName + ".jsonl")
+	if err != nil {
+		o.logger.Warning("Result streaming disabled: %v", err)
+	} else {
+		o.logger.Info("  Streaming results to %s", stream.filename)
+	}
 
 	// Create work queue
 	type job struct {
 		eventURL string
-		email    string
-		workerID int
-	}
-
-	jobs := make(chan job, totalTasks)
-	results := make(chan RegistrationResult, totalTasks)
-
-	// Worker pool
-	var wg sync.WaitGroup
-	for i := 0; i < o.maxWorkers; i++ {
-		wg.Add(1)
-		go func(workerID int) {
-			defer wg.Done()
-			worker := NewRegistrationWorker(workerID, proxies, o.headless, o.telegramChatID, o.logger)
-
-			for job := range jobs {
-				result := worker.ExecuteRegistration(
-					job.eventURL,
-					o.firstName,
-					o.lastName,
-					job.email,
-					o.organization,
-				)
-				results <- result
-			}
-		}(i)
+		template *FormTemplate
+		contact  Contact
+	}
+
+	var memoryMonitor *MemoryMonitor
+	if config.MaxMemory > 0 {
+		memoryMonitor = NewMemoryMonitor(config.MaxMemory, 2*time.Second, o.logger)
+		memoryMonitor.Start()
+		defer memoryMonitor.Stop()
+		o.logger.Info("  Memory limit: %d MB", config.MaxMemory>>20)
 	}
 
-	// Queue jobs
-	jobIndex := 0
-	for _, eventURL := range eventURLs {
-		for _, email := range emails {
-			jobs <- job{
-				eventURL: eventURL,
-				email:    email,
-				workerID: jobIndex % o.maxWorkers,
+	// Resolve each event's template once; the producer pairs them with contacts
+	type event struct {
+		url      string
+		template *FormTemplate
+	}
+	events := make([]event, 0, len(eventURLs))
+	for _, entry := range eventURLs {
+		eventURL, template, err := eventTemplate(entry, o.template)
+		if err != nil {
+			o.logger.Warning("%v - using %s", err, o.template.Name)
+		}
+		events = append(events, event{eventURL, template})
+	}
+	if o.done != nil {
+		done := 0
+		for _, ev := range events {
+			for _, contact := range contacts {
+				if o.done.Has(contact.Email, ev.url) {
+					done++
+				}
 			}
-			jobIndex++
+		}
+		if done > 0 {
+			o.logger.Info("Resuming: %d of %d tasks already done", done, totalTasks)
 		}
 	}
-	close(jobs)
+
+	// Fix the order before workers start drawing from random, so a seeded
+	// shuffle comes out the same every run
+	task := taskOrder(len(events), len(contacts), config.Interleave, config.Shuffle)
+
+	// Small buffers keep memory flat however many tasks the campaign has
+	jobs := make(chan job, 2*o.maxWorkers)
+	results := make(chan RegistrationResult, 2*o.maxWorkers)
+
+	// Worker pool; SetWorkers resizes it while the campaign runs
+	var pool *workerPool
+	pool = newWorkerPool(func(workerID int, retire <-chan struct{}) {
+		worker := o.newWorker(workerID, proxyPool)
+		pool.Add(worker)
+
+		for {
+			var job job
+			var ok bool
+			select {
+			case <-retire:
+				pool.Retire(worker)
+				return
+			case job, ok = <-jobs:
+			}
+			if !ok {
+				pool.Finish()
+				return
+			}
+
+			if o.Paused() {
+				// Give the browser slot back so other campaigns can use
+				// it while this one is paused
+				worker.Close()
+			}
+			o.waitWhilePaused()
+			if memoryMonitor != nil && memoryMonitor.Throttled() {
+				// Close the idle browser first, or memory could never drop
+				// while every worker waits with its browser open
+				worker.Close()
+				memoryMonitor.Wait(o.ctx.Done())
+			}
+			if o.aborted() || o.draining.Load() {
+				continue
+			}
+			if registrationRate.Wait(o.ctx) != nil {
+				continue
+			}
+			result := worker.ExecuteRegistration(
+				o.ctx,
+				job.eventURL,
+				job.template,
+				job.contact,
+			)
+			result.EventURL = normalizeEventURL(job.eventURL)
+			results <- result
+		}
+	})
+	pool.Start(o.maxWorkers)
+	o.pool.Store(pool)
+	defer o.pool.Store(nil)
+
+	// Feed jobs as workers take them, skipping tasks an earlier run already
+	// did. Stops early once the campaign is cancelled or draining; workers
+	// exit when jobs closes, which happens after every skipped result is sent.
+	go func() {
+		defer close(jobs)
+		for i := 0; i < totalTasks; i++ {
+			e, c := task(i)
+			ev := events[e]
+			contact := o.resolveContact(contacts[c])
+			if o.done != nil && o.done.Has(contact.Email, ev.url) {
+				results <- skippedResult(ev.url, contact, "Already done in a previous run")
+				continue
+			}
+			if o.draining.Load() {
+				return
+			}
+			select {
+			case jobs <- job{
+				eventURL: ev.url,
+				template: ev.template,
+				contact:  contact,
+			}:
+			case <-o.ctx.Done():
+				return
+			}
+		}
+	}()
 
 	// Collect results
 	go func() {
-		wg.Wait()
+		pool.Wait()
 		close(results)
 	}()
 
@@ -269,72 +1317,333 @@ func (o *RegistrationOrchestrator) Run(eventURLs, emails []string, proxies []Pro
 	successCount := 0
 
 	for result := range results {
-		allResults = append(allResults, result)
+		if stream != nil {
+			if err := stream.Write(result); err != nil {
+				o.logger.Warning("Failed to stream result, keeping results in memory: %v", err)
+				stream.Close()
+				allResults, _ = readResultStream(stream.filename)
+				stream = nil
+			}
+		}
+		if stream == nil {
+			allResults = append(allResults, result)
+		}
 		completed++
+		o.completed.Add(1)
+		// Tasks skipped from an earlier run finish instantly and would inflate the pace
+		if result.Status != "SKIPPED" {
+			o.pace.Add(time.Now())
+		}
 		if result.Status == "SUCCESS" {
 			successCount++
+			o.successful.Add(1)
+			if o.ledger != nil {
+				if err := o.ledger.Add(result.Email, result.EventURL); err != nil {
+					o.logger.Warning("Failed to save success ledger: %v", err)
+				}
+			}
+		}
+		if proxyStats != nil && result.Proxy != "" {
+			if success, counted := proxyOutcome(result); counted {
+				proxyStats.Record(result.Proxy, success)
+			}
+		}
+		if o.onResult != nil {
+			o.onResult(result)
 		}
 
 		elapsed := time.Since(startTime).Seconds()
-		o.logger.Info("Progress: %d/%d | Success: %d | Elapsed: %.0fs", completed, totalTasks, successCount, elapsed)
+		progress := fmt.Sprintf("Progress: %d/%d %s | Success: %d | Elapsed: %.0fs", completed, totalTasks, progressBar(completed, totalTasks), successCount, elapsed)
+		if eta, ok := o.ETA(); ok && completed < totalTasks {
+			progress += fmt.Sprintf(" | %s remaining", formatETA(eta))
+		}
+		o.logger.Info("%s", progress)
+
+		if config.FailFast && isFailFastStatus(result.Status) && !o.aborted() {
+			o.logger.Warning("Fail-fast: %s returned %s (%s) - aborting campaign", result.Email, result.Status, result.Message)
+			o.Abort()
+		}
+		if result.Category == CategoryBrowserLaunch && config.LaunchAbort && launchBreaker.Open() && !o.aborted() {
+			o.logger.Error("🧯 Aborting campaign: the browser keeps failing to launch. %s", launchFailureHint)
+			o.Abort()
+		}
+	}
+
+	if o.draining.Load() {
+		o.logger.Warning("Campaign interrupted: %d/%d tasks left unstarted", totalTasks-completed, totalTasks)
+	} else if o.aborted() {
+		o.logger.Warning("Campaign aborted: %d/%d tasks skipped", totalTasks-completed, totalTasks)
+	}
+
+	if stream != nil {
+		if err := stream.Close(); err != nil {
+			o.logger.Error("Failed to flush %s: %v", stream.filename, err)
+		}
+		streamed, err := readResultStream(stream.filename)
+		if err != nil {
+			o.logger.Error("Failed to read back %s: %v", stream.filename, err)
+		}
+		allResults = streamed
 	}
 
 	elapsed := time.Since(startTime)
-	o.printSummary(allResults, elapsed)
+	failureDigest.Flush(o.telegramChatID)
+	o.printSummary(baseName, allResults, elapsed)
+
+	if o.webhookURL != "" {
+		if err := postWebhook(o.webhookURL, newWebhookPayload(allResults, elapsed), o.logger); err != nil {
+			o.logger.Error("%v", err)
+		}
+	}
+
+	if proxyStats != nil {
+		if err := proxyStats.Save(); err != nil {
+			o.logger.Error("Failed to save proxy stats: %v", err)
+		}
+	}
 
 	return allResults
 }
 
-func (o *RegistrationOrchestrator) printSummary(results []RegistrationResult, elapsed time.Duration) {
+func (o *RegistrationOrchestrator) printSummary(baseName string, results []RegistrationResult, elapsed time.Duration) {
+	if o.dryRun {
+		o.printDryRunReport(results)
+		o.saveResults(baseName, results)
+		return
+	}
+
 	successful := 0
 	failed := 0
+	alreadyRegistered := 0
+	skipped := 0
+	launchFailed := 0
 
 	for _, r := range results {
-		if r.Status == "SUCCESS" {
+		switch {
+		case r.Status == "SUCCESS":
 			successful++
-		} else {
+		case r.Status == "ALREADY_REGISTERED":
+			alreadyRegistered++
+		case r.Status == "SKIPPED":
+			skipped++
+		case r.Category == CategoryBrowserLaunch:
+			launchFailed++
+		default:
 			failed++
 		}
 	}
 
 	successRate := 0.0
-	if len(results) > 0 {
-		successRate = float64(successful) / float64(len(results)) * 100
+	if ran := len(results) - skipped; ran > 0 {
+		successRate = float64(successful) / float64(ran) * 100
 	}
 
 	rate := float64(len(results)) / elapsed.Seconds()
 
-	o.logger.Info("\n" + strings.Repeat("=", 70))
+	o.logger.Info("\n%s", strings.Repeat("=", 70))
 	o.logger.Info("REGISTRATION CAMPAIGN SUMMARY")
-	o.logger.Info(strings.Repeat("=", 70))
+	o.logger.Info("%s", strings.Repeat("=", 70))
 	o.logger.Info("Total: %d", len(results))
 	o.logger.Info("✓ Successful: %d", successful)
+	o.logger.Info("↺ Already registered: %d", alreadyRegistered)
 	o.logger.Info("✗ Failed: %d", failed)
+	categories := countByCategory(results)
+	for _, category := range failureCategories {
+		if n := categories[category]; n > 0 && category != CategoryBrowserLaunch {
+			o.logger.Info("    %s: %d", category, n)
+		}
+	}
+	if launchFailed > 0 {
+		o.logger.Info("🧯 Browser failed to launch: %d", launchFailed)
+	}
+	if skipped > 0 {
+		o.logger.Info("⏭ Skipped (already done): %d", skipped)
+	}
 	o.logger.Info("Success Rate: %.1f%%", successRate)
 	o.logger.Info("Duration: %.1fs", elapsed.Seconds())
-	o.logger.Info("Rate: %.1f registrations/sec", rate)
-	o.logger.Info(strings.Repeat("=", 70))
+	if o.rateLimit > 0 {
+		o.logger.Info("Rate: %.1f registrations/sec (limit %s)", rate, formatRate(o.rateLimit))
+	} else {
+		o.logger.Info("Rate: %.1f registrations/sec", rate)
+	}
+	if events := summarizeByEvent(results); len(events) > 1 {
+		o.logger.Info("Per event:")
+		for _, e := range events {
+			o.logger.Info("  %-20s ✓ %d  ✗ %d  (%.0f%%)", e.Event, e.Successful, e.Failed, e.SuccessRate())
+		}
+	}
+	o.logger.Info("%s", strings.Repeat("=", 70))
 
-	o.saveResults(results)
+	o.saveResults(baseName, results)
 }
 
-func (o *RegistrationOrchestrator) saveResults(results []RegistrationResult) {
-	outputFile := fmt.Sprintf("results_%s.json", time.Now().Format("20060102_150405"))
+// printDryRunReport logs which events have all their form fields
+func (o *RegistrationOrchestrator) printDryRunReport(results []RegistrationResult) {
+	ready := 0
+	o.logger.Info("\n%s", strings.Repeat("=", 70))
+	o.logger.Info("DRY RUN REPORT")
+	o.logger.Info("%s", strings.Repeat("=", 70))
+	for _, r := range results {
+		if r.Status == "DRY_RUN" && r.Category == CategoryNone {
+			ready++
+			o.logger.Info("✓ %-20s %s", r.Event, r.Message)
+		} else {
+			o.logger.Warning("✗ %-20s %s", r.Event, r.Message)
+		}
+	}
+	o.logger.Info("%d/%d events ready", ready, len(results))
+	o.logger.Info("%s", strings.Repeat("=", 70))
+}
 
-	data, err := json.MarshalIndent(results, "", "  ")
+// EventSummary counts the results for one event
+type EventSummary struct {
+	Event      string
+	Successful int
+	Failed     int
+}
+
+// SuccessRate returns the event's success percentage
+func (e EventSummary) SuccessRate() float64 {
+	total := e.Successful + e.Failed
+	if total == 0 {
+		return 0
+	}
+	return float64(e.Successful) / float64(total) * 100
+}
+
+// eventKey is the grouping key for an event URL or ID, matching RegistrationResult.Event
+func eventKey(event string) string {
+	return truncateString(lastPathSegment(strings.TrimSpace(event)), 20)
+}
+
+// normalizeEventURL identifies an event by its full URL: scheme and host
+// lower-cased, the path without a trailing slash, and the query with its
+// parameters sorted. The fragment is dropped. Values that aren't absolute
+// URLs are only trimmed.
+func normalizeEventURL(event string) string {
+	event = strings.TrimSpace(event)
+	u, err := url.Parse(event)
+	if err != nil || u.Host == "" {
+		return event
+	}
+	normalized := strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host) + strings.TrimRight(u.EscapedPath(), "/")
+	if u.RawQuery != "" {
+		normalized += "?" + u.Query().Encode()
+	}
+	return normalized
+}
+
+// summarizeByEvent groups results by event, sorted by event key
+func summarizeByEvent(results []RegistrationResult) []EventSummary {
+	index := make(map[string]int)
+	var events []EventSummary
+	for _, r := range results {
+		i, ok := index[r.Event]
+		if !ok {
+			i = len(events)
+			index[r.Event] = i
+			events = append(events, EventSummary{Event: r.Event})
+		}
+		if r.Status == "SUCCESS" {
+			events[i].Successful++
+		} else if r.Status != "SKIPPED" {
+			events[i].Failed++
+		}
+	}
+	sort.Slice(events, func(a, b int) bool { return events[a].Event < events[b].Event })
+	return events
+}
+
+// saveResults writes the final results files as baseName.json and/or baseName.csv
+func (o *RegistrationOrchestrator) saveResults(baseName string, results []RegistrationResult) {
+	if config.OutputFormat != "csv" {
+		outputFile := baseName + ".json"
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			o.logger.Error("Failed to marshal results: %v", err)
+		} else if err := os.WriteFile(outputFile, data, 0644); err != nil {
+			o.logger.Error("Failed to save results: %v", err)
+		} else {
+			o.logger.Info("Results saved to %s", outputFile)
+		}
+	}
+
+	if config.OutputFormat != "json" {
+		outputFile := baseName + ".csv"
+		if err := saveResultsCSV(outputFile, results); err != nil {
+			o.logger.Error("Failed to save CSV results: %v", err)
+		} else {
+			o.logger.Info("Results saved to %s", outputFile)
+		}
+	}
+
+	if config.GroupBy == "email" {
+		o.saveResultsByEmail(results)
+	}
+}
+
+// saveResultsByEmail writes one results/<email>.json file per email
+func (o *RegistrationOrchestrator) saveResultsByEmail(results []RegistrationResult) {
+	const outputDir = "results"
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		o.logger.Error("Failed to create %s directory: %v", outputDir, err)
+		return
+	}
+
+	byEmail := make(map[string][]RegistrationResult)
+	for _, r := range results {
+		byEmail[r.Email] = append(byEmail[r.Email], r)
+	}
+
+	for email, emailResults := range byEmail {
+		data, err := json.MarshalIndent(emailResults, "", "  ")
+		if err != nil {
+			o.logger.Error("Failed to marshal results for %s: %v", email, err)
+			continue
+		}
+
+		outputFile := filepath.Join(outputDir, sanitizeFilename(email)+".json")
+		if err := os.WriteFile(outputFile, data, 0644); err != nil {
+			o.logger.Error("Failed to save results for %s: %v", email, err)
+		}
+	}
+
+	o.logger.Info("Per-email results saved to %s/ (%d files)", outputDir, len(byEmail))
+}
+
+// printProxyStats logs the persisted proxy ranking
+func printProxyStats(logger *Logger) {
+	stats, err := LoadProxyStats(config.ProxyStatsFile)
 	if err != nil {
-		o.logger.Error("Failed to marshal results: %v", err)
+		logger.Error("%v", err)
 		return
 	}
 
-	if err := os.WriteFile(outputFile, data, 0644); err != nil {
-		o.logger.Error("Failed to save results: %v", err)
+	ranked := stats.Ranked()
+	if len(ranked) == 0 {
+		logger.Info("No proxy stats recorded yet in %s", config.ProxyStatsFile)
 		return
 	}
 
-	o.logger.Info("Results saved to %s", outputFile)
+	logger.Info("Proxy ranking (%d proxies, %s):", len(ranked), config.ProxyStatsFile)
+	for _, line := range formatProxyRanking(ranked, len(ranked)) {
+		logger.Info("  %s", line)
+	}
+}
+
+// capWorkersToProxies limits workers to perProxy workers for each available proxy
+func capWorkersToProxies(workers, proxyCount, perProxy int) int {
+	if proxyCount == 0 {
+		return workers
+	}
+	if perProxy < 1 {
+		perProxy = 1
+	}
+	return min(workers, proxyCount*perProxy)
 }
 
+// getSystemInfo describes the build and host environment for startup logs
 func getSystemInfo() string {
-	return fmt.Sprintf("Go version: %s", strings.TrimPrefix(os.Getenv("GO_VERSION"), "go"))
+	return fmt.Sprintf("%s | OS/Arch: %s/%s | CPUs: %d",
+		versionString(), runtime.GOOS, runtime.GOARCH, runtime.NumCPU())
 }
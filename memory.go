@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// parseByteSize parses sizes like "512M", "4G" or "2048" (megabytes when no unit)
+func parseByteSize(s string) (uint64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.TrimSuffix(s, "B")
+
+	multiplier := uint64(1 << 20)
+	switch {
+	case strings.HasSuffix(s, "K"):
+		multiplier, s = 1<<10, strings.TrimSuffix(s, "K")
+	case strings.HasSuffix(s, "M"):
+		multiplier, s = 1<<20, strings.TrimSuffix(s, "M")
+	case strings.HasSuffix(s, "G"):
+		multiplier, s = 1<<30, strings.TrimSuffix(s, "G")
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("invalid size %q (expected e.g. 512M or 4G)", s)
+	}
+	return uint64(value * float64(multiplier)), nil
+}
+
+// processTreeRSS returns the resident memory of root and all its descendants.
+// It reads /proc and is only supported on Linux.
+func processTreeRSS(root int) (uint64, error) {
+	statFiles, err := filepath.Glob("/proc/[0-9]*/stat")
+	if err != nil || len(statFiles) == 0 {
+		return 0, fmt.Errorf("process memory monitoring requires /proc")
+	}
+
+	children := make(map[int][]int)
+	for _, statFile := range statFiles {
+		data, err := os.ReadFile(statFile)
+		if err != nil {
+			continue // Process exited while scanning
+		}
+		// Fields after the parenthesized command name: state ppid ...
+		stat := string(data)
+		fields := strings.Fields(stat[strings.LastIndex(stat, ")")+1:])
+		if len(fields) < 2 {
+			continue
+		}
+		pid, _ := strconv.Atoi(filepath.Base(filepath.Dir(statFile)))
+		ppid, _ := strconv.Atoi(fields[1])
+		children[ppid] = append(children[ppid], pid)
+	}
+
+	pageSize := uint64(os.Getpagesize())
+	var total uint64
+	queue := []int{root}
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = append(queue[1:], children[pid]...)
+
+		data, err := os.ReadFile(fmt.Sprintf("/proc/%d/statm", pid))
+		if err != nil {
+			continue
+		}
+		fields := strings.Fields(string(data))
+		if len(fields) < 2 {
+			continue
+		}
+		pages, _ := strconv.ParseUint(fields[1], 10, 64)
+		total += pages * pageSize
+	}
+	return total, nil
+}
+
+// memoryWaitLimit is how long a worker waits for memory to drop before going
+// ahead anyway, so a limit the campaign can't get under doesn't hang it
+const memoryWaitLimit = 5 * time.Minute
+
+// MemoryMonitor samples the process tree's memory and throttles job dispatch above a limit
+type MemoryMonitor struct {
+	limit     uint64
+	interval  time.Duration
+	maxWait   time.Duration
+	throttled atomic.Bool
+	stop      chan struct{}
+	logger    *Logger
+}
+
+// NewMemoryMonitor creates a monitor for limit bytes sampled every interval
+func NewMemoryMonitor(limit uint64, interval time.Duration, logger *Logger) *MemoryMonitor {
+	return &MemoryMonitor{
+		limit:    limit,
+		interval: interval,
+		maxWait:  memoryWaitLimit,
+		stop:     make(chan struct{}),
+		logger:   logger,
+	}
+}
+
+// Start begins sampling in the background until Stop is called
+func (m *MemoryMonitor) Start() {
+	go func() {
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			rss, err := processTreeRSS(os.Getpid())
+			if err != nil {
+				m.logger.Warning("Memory monitor disabled: %v", err)
+				m.throttled.Store(false)
+				return
+			}
+
+			over := rss > m.limit
+			if over != m.throttled.Load() {
+				if over {
+					m.logger.Warning("🧠 Memory %d MB exceeds limit %d MB - pausing new jobs", rss>>20, m.limit>>20)
+				} else {
+					m.logger.Info("🧠 Memory back to %d MB - resuming jobs", rss>>20)
+				}
+				m.throttled.Store(over)
+			}
+
+			select {
+			case <-m.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop ends sampling
+func (m *MemoryMonitor) Stop() {
+	close(m.stop)
+}
+
+// Throttled reports whether memory is over the limit
+func (m *MemoryMonitor) Throttled() bool {
+	return m.throttled.Load()
+}
+
+// Wait blocks while memory is over the limit, returning early when abort is
+// closed. After maxWait it logs a warning and returns anyway.
+func (m *MemoryMonitor) Wait(abort <-chan struct{}) {
+	deadline := time.Now().Add(m.maxWait)
+	for m.throttled.Load() {
+		if time.Now().After(deadline) {
+			m.logger.Warning("🧠 Memory still over %d MB after %v - continuing anyway", m.limit>>20, m.maxWait)
+			return
+		}
+		select {
+		case <-abort:
+			return
+		case <-time.After(m.interval):
+		}
+	}
+}
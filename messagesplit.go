@@ -0,0 +1,129 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// htmlTag matches an opening or closing tag of Telegram's HTML parse mode
+var htmlTag = regexp.MustCompile(`<(/?)([a-zA-Z-]+)[^>]*>`)
+
+// splitMessage breaks text into parts of at most limit bytes, cutting at line
+// boundaries where it can and mid-line only when a single line is too long.
+// A cut never lands inside a tag or entity, and tags still open at a cut are
+// closed at the end of the part and reopened at the start of the next, so
+// each part is valid HTML on its own.
+func splitMessage(text string, limit int) []string {
+	if len(text) <= limit {
+		return []string{text}
+	}
+
+	var parts []string
+	var current strings.Builder
+	var open []string // Opening tags in effect at the end of current
+	prefix := 0       // Length of the reopened tags current starts with
+
+	flush := func() {
+		parts = append(parts, strings.TrimRight(current.String(), "\n")+closeTags(open))
+		current.Reset()
+		for _, tag := range open {
+			current.WriteString(tag)
+		}
+		prefix = current.Len()
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		after := openTags(open, line)
+		if current.Len()+len(line)+len(closeTags(after)) <= limit {
+			current.WriteString(line)
+			open = after
+			continue
+		}
+		if current.Len() > prefix {
+			flush()
+			i--
+			continue
+		}
+
+		// The line doesn't fit even in an empty part: send what fits and
+		// carry the rest over as a line of its own
+		cut := splitPoint(line, open, limit-current.Len())
+		current.WriteString(line[:cut])
+		open = openTags(open, line[:cut])
+		flush()
+		lines[i] = line[cut:]
+		i--
+	}
+	if current.Len() > prefix {
+		parts = append(parts, strings.TrimRight(current.String(), "\n")+closeTags(open))
+	}
+	return parts
+}
+
+// splitPoint returns how much of line fits in room bytes, closing tags
+// included, preferring to cut after a space. It cuts somewhere even if a tag
+// or entity is longer than room, so splitting always makes progress.
+func splitPoint(line string, open []string, room int) int {
+	cut := room
+	if cut > len(line) {
+		cut = len(line)
+	}
+	for cut > 0 && (cut < len(line) && !utf8.RuneStart(line[cut]) || !safeCut(line[:cut]) ||
+		cut+len(closeTags(openTags(open, line[:cut]))) > room) {
+		cut--
+	}
+	if cut == 0 {
+		for cut = 1; cut < len(line) && !utf8.RuneStart(line[cut]); cut++ {
+		}
+		return cut
+	}
+	if space := strings.LastIndex(line[:cut], " "); space > cut/2 && safeCut(line[:space+1]) {
+		return space + 1
+	}
+	return cut
+}
+
+// safeCut reports whether s ends outside any tag or entity
+func safeCut(s string) bool {
+	if strings.LastIndex(s, "<") > strings.LastIndex(s, ">") {
+		return false
+	}
+	amp := strings.LastIndex(s, "&")
+	return amp < 0 || strings.Contains(s[amp:], ";")
+}
+
+// openTags returns the opening tags still in effect after s, given those
+// open before it
+func openTags(open []string, s string) []string {
+	result := append([]string(nil), open...)
+	for _, match := range htmlTag.FindAllStringSubmatch(s, -1) {
+		if match[1] == "" {
+			result = append(result, match[0])
+			continue
+		}
+		for j := len(result) - 1; j >= 0; j-- {
+			if tagName(result[j]) == strings.ToLower(match[2]) {
+				result = append(result[:j], result[j+1:]...)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// closeTags closes open in reverse order
+func closeTags(open []string) string {
+	var sb strings.Builder
+	for i := len(open) - 1; i >= 0; i-- {
+		sb.WriteString("</" + tagName(open[i]) + ">")
+	}
+	return sb.String()
+}
+
+// tagName is the lower-cased element name of an opening tag
+func tagName(tag string) string {
+	return strings.ToLower(htmlTag.FindStringSubmatch(tag)[2])
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// MetricsSnapshot is the campaign progress served at /metrics
+type MetricsSnapshot struct {
+	Running    bool    `json:"running"`
+	Completed  int     `json:"completed"`
+	Total      int     `json:"total"`
+	Successful int     `json:"successful"`
+	Failed     int     `json:"failed"`
+	Rate       float64 `json:"rate"`   // Completed tasks per second of the current campaign
+	Uptime     float64 `json:"uptime"` // Seconds since the process started
+}
+
+// RegistrationMetrics records per-task metrics as workers finish registrations
+type RegistrationMetrics interface {
+	TaskStarted()
+	TaskFinished(status string, duration time.Duration)
+}
+
+// noopRegistrationMetrics is used unless --prometheus is enabled
+type noopRegistrationMetrics struct{}
+
+func (noopRegistrationMetrics) TaskStarted()                       {}
+func (noopRegistrationMetrics) TaskFinished(string, time.Duration) {}
+
+// registrationMetrics receives every worker's task metrics
+var registrationMetrics RegistrationMetrics = noopRegistrationMetrics{}
+
+// CampaignTracker follows the running campaigns (or the last one to finish)
+// for the metrics endpoint. Bot chats run campaigns side by side, so their
+// progress is summed.
+type CampaignTracker struct {
+	mu         sync.Mutex
+	active     map[*RegistrationOrchestrator]*trackedCampaign
+	last       *trackedCampaign // Most recently finished campaign
+	started    time.Time
+	prometheus http.Handler // Served at /metrics/prometheus when set
+}
+
+// trackedCampaign is one campaign's orchestrator and run time
+type trackedCampaign struct {
+	orchestrator *RegistrationOrchestrator
+	start        time.Time
+	end          time.Time // Zero while running
+}
+
+// campaignMetrics is the tracker shared by CLI and bot mode campaigns
+var campaignMetrics = NewCampaignTracker()
+
+// NewCampaignTracker creates a tracker with no campaign
+func NewCampaignTracker() *CampaignTracker {
+	return &CampaignTracker{
+		active:  make(map[*RegistrationOrchestrator]*trackedCampaign),
+		started: time.Now(),
+	}
+}
+
+// Begin records o as a running campaign
+func (t *CampaignTracker) Begin(o *RegistrationOrchestrator) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.active[o] = &trackedCampaign{orchestrator: o, start: time.Now()}
+}
+
+// End marks o's campaign finished; its final counts stay visible until
+// another campaign runs
+func (t *CampaignTracker) End(o *RegistrationOrchestrator) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	campaign, ok := t.active[o]
+	if !ok {
+		return
+	}
+	delete(t.active, o)
+	campaign.end = time.Now()
+	t.last = campaign
+}
+
+// Snapshot returns the current metrics, summed over the running campaigns
+func (t *CampaignTracker) Snapshot() MetricsSnapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	snapshot := MetricsSnapshot{
+		Running: len(t.active) > 0,
+		Uptime:  time.Since(t.started).Seconds(),
+	}
+	campaigns := make([]*trackedCampaign, 0, len(t.active))
+	for _, campaign := range t.active {
+		campaigns = append(campaigns, campaign)
+	}
+	if len(campaigns) == 0 && t.last != nil {
+		campaigns = append(campaigns, t.last)
+	}
+
+	for _, campaign := range campaigns {
+		completed, successful, total := campaign.orchestrator.Progress()
+		snapshot.Completed += completed
+		snapshot.Successful += successful
+		snapshot.Total += total
+
+		end := campaign.end
+		if end.IsZero() {
+			end = time.Now()
+		}
+		if elapsed := end.Sub(campaign.start).Seconds(); elapsed > 0 {
+			snapshot.Rate += float64(completed) / elapsed
+		}
+	}
+	snapshot.Failed = snapshot.Completed - snapshot.Successful
+	return snapshot
+}
+
+// Handler serves /healthz and /metrics
+func (t *CampaignTracker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":  "ok",
+			"running": t.Snapshot().Running,
+		})
+	})
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(t.Snapshot())
+	})
+	if t.prometheus != nil {
+		mux.Handle("/metrics/prometheus", t.prometheus)
+	}
+	return mux
+}
+
+// enablePrometheus routes worker metrics to a Prometheus exporter served by tracker
+func enablePrometheus(tracker *CampaignTracker) error {
+	recorder, handler, err := newPrometheusMetrics()
+	if err != nil {
+		return err
+	}
+	registrationMetrics = recorder
+	tracker.prometheus = handler
+	return nil
+}
+
+// startMetricsServer serves the tracker's endpoints on addr in the background
+func startMetricsServer(addr string, tracker *CampaignTracker, logger *Logger) (*http.Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &http.Server{
+		Handler:           tracker.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Error("Metrics server stopped: %v", err)
+		}
+	}()
+
+	logger.Info("Metrics available at http://%s/metrics", listener.Addr())
+	return server, nil
+}
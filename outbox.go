@@ -0,0 +1,121 @@
+package main
+
+import (
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	outboxSize  = 100              // Messages queued per chat before new ones are dropped
+	outboxIdle  = time.Minute      // How long a chat's sender lingers with nothing to send
+	outboxDrain = 30 * time.Second // Longest a finished CLI run waits for queued sends
+)
+
+// TelegramOutbox queues alerts and photos per chat and sends them from one
+// goroutine per chat, so workers reporting results never wait on Telegram's
+// per-chat pacing or 429 backoff
+type TelegramOutbox struct {
+	size    int
+	queues  map[string]chan func()
+	pending atomic.Int64 // Sends queued or in progress
+	mu      sync.Mutex
+}
+
+// telegramOutbox carries the alerts and screenshots of every campaign
+var telegramOutbox = NewTelegramOutbox(outboxSize)
+
+// NewTelegramOutbox queues up to size sends per chat
+func NewTelegramOutbox(size int) *TelegramOutbox {
+	return &TelegramOutbox{size: size, queues: make(map[string]chan func())}
+}
+
+// Enqueue schedules send on chatID's sender without blocking. It reports
+// false, dropping send, when the chat already has size sends waiting.
+func (o *TelegramOutbox) Enqueue(chatID string, send func()) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	queue := o.queues[chatID]
+	if queue == nil {
+		queue = make(chan func(), o.size)
+		o.queues[chatID] = queue
+		go o.run(chatID, queue)
+	}
+	select {
+	case queue <- send:
+		o.pending.Add(1)
+		return true
+	default:
+		return false
+	}
+}
+
+// Drain waits up to timeout for every queued send to finish, reporting
+// whether they all did. The process exits after a CLI campaign, taking
+// anything still queued with it.
+func (o *TelegramOutbox) Drain(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for o.pending.Load() > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return true
+}
+
+// run sends chatID's queue in order, exiting once it has been idle for
+// outboxIdle
+func (o *TelegramOutbox) run(chatID string, queue chan func()) {
+	idle := time.NewTimer(outboxIdle)
+	defer idle.Stop()
+	for {
+		select {
+		case send := <-queue:
+			send()
+			o.pending.Add(-1)
+			idle.Reset(outboxIdle)
+		case <-idle.C:
+			// Enqueue adds under o.mu, so an empty queue here stays empty
+			o.mu.Lock()
+			if len(queue) == 0 {
+				delete(o.queues, chatID)
+				o.mu.Unlock()
+				return
+			}
+			o.mu.Unlock()
+			idle.Reset(outboxIdle)
+		}
+	}
+}
+
+// queueTelegramAlert sends message to chatID in the background, reporting
+// whether it was queued
+func queueTelegramAlert(message, chatID string, logger *Logger) bool {
+	if chatID == "" || config.TelegramAPI == "" {
+		return sendTelegramAlert(message, chatID, logger)
+	}
+	if !telegramOutbox.Enqueue(chatID, func() { sendTelegramAlert(message, chatID, logger) }) {
+		logger.Warning("Telegram queue for chat %s is full - dropped an alert", chatID)
+		return false
+	}
+	return true
+}
+
+// queueTelegramPhoto uploads the image at path to chatID in the background
+// and removes the file once it has been sent
+func queueTelegramPhoto(path, caption, chatID string, logger *Logger) bool {
+	send := func() {
+		if sendTelegramPhoto(path, caption, chatID, logger) {
+			if err := os.Remove(path); err != nil {
+				logger.Warning("Failed to remove %s: %v", path, err)
+			}
+		}
+	}
+	if !telegramOutbox.Enqueue(chatID, send) {
+		logger.Warning("Telegram queue for chat %s is full - kept %s", chatID, path)
+		return false
+	}
+	return true
+}
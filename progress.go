@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	etaWindow     = 50 // Recent completions the ETA rate is measured over
+	etaMinSamples = 3  // Completions needed before an ETA is shown
+	progressWidth = 20 // Cells in a progress bar
+)
+
+// CompletionRate remembers when the last etaWindow tasks completed, so the
+// ETA follows the campaign's current pace rather than its slow first launches
+type CompletionRate struct {
+	times []time.Time // Ring of completion times
+	next  int
+	mu    sync.Mutex
+}
+
+// NewCompletionRate returns an empty CompletionRate
+func NewCompletionRate() *CompletionRate {
+	return &CompletionRate{times: make([]time.Time, 0, etaWindow)}
+}
+
+// Add records a task completing at t
+func (r *CompletionRate) Add(t time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.times) < etaWindow {
+		r.times = append(r.times, t)
+		return
+	}
+	r.times[r.next] = t
+	r.next = (r.next + 1) % etaWindow
+}
+
+// ETA estimates how long remaining tasks take at the recent pace, measured
+// from the oldest remembered completion to now so a stall pushes it out. It
+// reports false until etaMinSamples tasks have completed.
+func (r *CompletionRate) ETA(remaining int, now time.Time) (time.Duration, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.times) < etaMinSamples {
+		return 0, false
+	}
+	oldest := r.times[0]
+	if len(r.times) == etaWindow {
+		oldest = r.times[r.next]
+	}
+	span := now.Sub(oldest)
+	if span <= 0 {
+		return 0, false
+	}
+	return time.Duration(float64(span) / float64(len(r.times)) * float64(remaining)), true
+}
+
+// progressBar draws done out of total as a bar with its percentage
+func progressBar(done, total int) string {
+	if total <= 0 {
+		return "[" + strings.Repeat("░", progressWidth) + "] 0%"
+	}
+	done = min(max(done, 0), total)
+	filled := done * progressWidth / total
+	return fmt.Sprintf("[%s%s] %d%%", strings.Repeat("█", filled), strings.Repeat("░", progressWidth-filled), done*100/total)
+}
+
+// formatETA rounds a remaining time for display, e.g. "~14m" or "~2h05m"
+func formatETA(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "<1m"
+	case d < time.Hour:
+		return fmt.Sprintf("~%dm", int(d.Round(time.Minute)/time.Minute))
+	default:
+		d = d.Round(time.Minute)
+		return fmt.Sprintf("~%dh%02dm", int(d/time.Hour), int(d%time.Hour/time.Minute))
+	}
+}
+
+// progressPollInterval is how often the bot checks whether a progress update is due
+const progressPollInterval = 5 * time.Second
+
+// progressNotifier decides when a campaign's chat gets a progress update:
+// every interval, and whenever completion crosses a multiple of step percent.
+// A zero interval or step disables that trigger.
+type progressNotifier struct {
+	interval time.Duration
+	step     int
+	last     time.Time // When the last update went out
+	lastStep int       // Step percent the last update covered
+}
+
+// newProgressNotifier starts counting the interval from start
+func newProgressNotifier(interval time.Duration, step int, start time.Time) *progressNotifier {
+	return &progressNotifier{interval: interval, step: step, last: start}
+}
+
+// due reports whether an update should go out now, and if so counts it as sent.
+// Nothing is due once every task has completed; the final summary covers that.
+func (p *progressNotifier) due(completed, total int, now time.Time) bool {
+	if total == 0 || completed >= total {
+		return false
+	}
+	stepReached := 0
+	if p.step > 0 {
+		stepReached = completed * 100 / total / p.step * p.step
+	}
+	if (p.step > 0 && stepReached > p.lastStep) || (p.interval > 0 && now.Sub(p.last) >= p.interval) {
+		p.last = now
+		p.lastStep = stepReached
+		return true
+	}
+	return false
+}
+
+// formatProgressUpdate is the progress message pushed to a campaign's chat
+func formatProgressUpdate(completed, successful, total int, eta time.Duration, etaKnown bool) string {
+	successRate := 0.0
+	if completed > 0 {
+		successRate = float64(successful) / float64(completed) * 100
+	}
+	msg := fmt.Sprintf(
+		"📈 <b>Campaign Progress</b>\n\n"+
+			"<code>%s</code>\n"+
+			"📊 Completed: %d/%d\n"+
+			"✅ Success rate: %.1f%%",
+		progressBar(completed, total), completed, total, successRate,
+	)
+	if etaKnown {
+		msg += fmt.Sprintf("\n⏳ ETA: %s remaining", formatETA(eta))
+	}
+	return msg + "\n\nSend /quiet to mute these updates"
+}
@@ -0,0 +1,55 @@
+//go:build prometheus
+
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// prometheusMetrics exports per-registration metrics in Prometheus format
+type prometheusMetrics struct {
+	registrations *prometheus.CounterVec
+	workersActive prometheus.Gauge
+	duration      prometheus.Histogram
+}
+
+// newPrometheusMetrics registers the collectors and returns the recorder and scrape handler
+func newPrometheusMetrics() (RegistrationMetrics, http.Handler, error) {
+	m := &prometheusMetrics{
+		registrations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "eventblaster_registrations_total",
+			Help: "Finished registration tasks by result status.",
+		}, []string{"status"}),
+		workersActive: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "eventblaster_workers_active",
+			Help: "Workers currently executing a registration.",
+		}),
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "eventblaster_registration_duration_seconds",
+			Help:    "Time to finish a registration task, including retries.",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 10), // 1s to ~8.5min
+		}),
+	}
+
+	registry := prometheus.NewRegistry()
+	for _, collector := range []prometheus.Collector{m.registrations, m.workersActive, m.duration} {
+		if err := registry.Register(collector); err != nil {
+			return nil, nil, err
+		}
+	}
+	return m, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}), nil
+}
+
+func (m *prometheusMetrics) TaskStarted() {
+	m.workersActive.Inc()
+}
+
+func (m *prometheusMetrics) TaskFinished(status string, duration time.Duration) {
+	m.workersActive.Dec()
+	m.registrations.WithLabelValues(status).Inc()
+	m.duration.Observe(duration.Seconds())
+}
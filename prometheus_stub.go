@@ -0,0 +1,14 @@
+//go:build !prometheus
+
+package main
+
+import (
+	"errors"
+	"net/http"
+)
+
+// newPrometheusMetrics is unavailable unless built with -tags prometheus,
+// which keeps the client library out of default builds
+func newPrometheusMetrics() (RegistrationMetrics, http.Handler, error) {
+	return nil, nil, errors.New("built without Prometheus support (rebuild with -tags prometheus)")
+}
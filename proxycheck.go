@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// ipCheckURL answers with the caller's public IP as {"ip":"..."}
+const ipCheckURL = "https://api.ipify.org?format=json"
+
+// directIP caches this machine's own public IP, looked up once without a proxy
+var directIP struct {
+	once sync.Once
+	ip   string
+}
+
+// directEgressIP returns the public IP of a direct connection, or "" if it
+// couldn't be looked up
+func directEgressIP(logger *Logger) string {
+	directIP.once.Do(func() {
+		client := &http.Client{
+			Timeout:   proxyVerifyTimeout,
+			Transport: &http.Transport{Proxy: nil},
+		}
+		resp, err := client.Get(ipCheckURL)
+		if err != nil {
+			logger.Warning("Could not look up the direct IP, proxy egress won't be compared: %v", err)
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		ip, err := parseIPCheck(string(body))
+		if err != nil {
+			logger.Warning("Could not look up the direct IP, proxy egress won't be compared: %v", err)
+			return
+		}
+		directIP.ip = ip
+		logger.Debug("Direct IP: %s", ip)
+	})
+	return directIP.ip
+}
+
+// parseIPCheck reads the IP out of an ipCheckURL response
+func parseIPCheck(body string) (string, error) {
+	var check struct {
+		IP string `json:"ip"`
+	}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(body)), &check); err != nil {
+		return "", fmt.Errorf("unexpected IP check response %q", body)
+	}
+	if net.ParseIP(check.IP) == nil {
+		return "", fmt.Errorf("IP check returned %q, not an IP", check.IP)
+	}
+	return check.IP, nil
+}
+
+// proxyAuthProblem explains why proxy's credentials can't work, or returns ""
+// if they can. Chromium only answers auth challenges from HTTP(S) proxies;
+// credentials on a SOCKS proxy are silently dropped.
+func proxyAuthProblem(proxy *ProxyConfig) string {
+	if proxy.Username == "" || !strings.HasPrefix(strings.ToLower(proxy.Server), "socks") {
+		return ""
+	}
+	return fmt.Sprintf("Proxy %s needs a username/password, which Chromium can't send to SOCKS proxies - use its HTTP endpoint or allow-list this server's IP", proxy.Server)
+}
+
+// verifyProxy loads the IP check page through the context's proxy. It fails
+// the attempt when the proxy rejects its credentials or traffic isn't going
+// through it at all; an IP check that is merely unreachable only warns.
+func verifyProxy(page playwright.Page, logger *Logger) (string, FailureCategory) {
+	logger.Info("🔍 Verifying proxy connection...")
+	resp, err := page.Goto(ipCheckURL, playwright.PageGotoOptions{
+		Timeout: milliseconds(proxyVerifyTimeout),
+	})
+	if err != nil {
+		if message, category := navFailure(err); category == CategoryProxyError {
+			return strings.Replace(message, "Failed to load page", "Proxy check failed", 1), category
+		}
+		logger.Warning("⚠️  Could not verify proxy IP: %v", err)
+		return "", ""
+	}
+	if resp != nil && resp.Status() == http.StatusProxyAuthRequired {
+		return "Proxy authentication failed (HTTP 407) - check the proxy username and password", CategoryProxyError
+	}
+
+	body, _ := page.Evaluate("() => document.body.innerText")
+	ip, err := parseIPCheck(fmt.Sprint(body))
+	if err != nil {
+		logger.Warning("⚠️  Could not verify proxy IP: %v", err)
+		return "", ""
+	}
+	if direct := directEgressIP(logger); direct != "" && ip == direct {
+		return fmt.Sprintf("Proxy not in effect: traffic left from this server's own IP %s", ip), CategoryProxyError
+	}
+	logger.Info("✅ Proxy IP check: %s", ip)
+	return "", ""
+}
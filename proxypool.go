@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ProxyPool hands out proxies round-robin to all workers so traffic is spread
+// evenly across the total task count rather than pinned per worker. Proxies
+// that fail maxFailures times in a row are taken out of rotation.
+type ProxyPool struct {
+	proxies     []ProxyConfig
+	next        atomic.Uint64
+	maxFailures int // 0 keeps failing proxies in rotation
+	mu          sync.Mutex
+	failures    map[string]int  // Consecutive proxy errors by server
+	suspect     map[string]bool // Servers removed from rotation
+}
+
+// NewProxyPool creates a pool over proxies; an empty pool means direct connections
+func NewProxyPool(proxies []ProxyConfig) *ProxyPool {
+	return &ProxyPool{
+		proxies:     proxies,
+		maxFailures: config.ProxyMaxFailures,
+		failures:    make(map[string]int),
+		suspect:     make(map[string]bool),
+	}
+}
+
+// Next returns the next proxy in rotation, or nil when the pool is empty.
+// Suspect proxies are skipped unless every proxy is suspect, so traffic never
+// silently falls back to a direct connection.
+func (p *ProxyPool) Next() *ProxyConfig {
+	if p == nil || len(p.proxies) == 0 {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for tries := 0; tries < len(p.proxies); tries++ {
+		i := (p.next.Add(1) - 1) % uint64(len(p.proxies))
+		if proxy := p.proxies[i]; !p.suspect[proxy.Server] {
+			return &proxy
+		}
+	}
+	i := (p.next.Add(1) - 1) % uint64(len(p.proxies))
+	proxy := p.proxies[i]
+	return &proxy
+}
+
+// ReportFailure records a proxy-level error for server and reports whether
+// it just crossed maxFailures and was removed from rotation
+func (p *ProxyPool) ReportFailure(server string) bool {
+	if p == nil || p.maxFailures <= 0 {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failures[server]++
+	if p.failures[server] >= p.maxFailures && !p.suspect[server] {
+		p.suspect[server] = true
+		return true
+	}
+	return false
+}
+
+// ReportSuccess clears the failure count of server
+func (p *ProxyPool) ReportSuccess(server string) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.failures, server)
+}
+
+// Healthy returns the number of proxies still in rotation
+func (p *ProxyPool) Healthy() int {
+	if p == nil {
+		return 0
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	healthy := 0
+	for _, proxy := range p.proxies {
+		if !p.suspect[proxy.Server] {
+			healthy++
+		}
+	}
+	return healthy
+}
+
+// Len returns the number of proxies in the pool
+func (p *ProxyPool) Len() int {
+	if p == nil {
+		return 0
+	}
+	return len(p.proxies)
+}
+
+// filterProxiesByCountry keeps the proxies tagged with country (case-insensitive).
+// An empty country keeps them all; it is an error when none match.
+func filterProxiesByCountry(proxies []ProxyConfig, country string) ([]ProxyConfig, error) {
+	if country == "" {
+		return proxies, nil
+	}
+
+	var matched []ProxyConfig
+	for _, proxy := range proxies {
+		if strings.EqualFold(proxy.Country, country) {
+			matched = append(matched, proxy)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("none of the %d proxies are tagged %s (add the country as a trailing token, e.g. host:port:user:pass:%s)",
+			len(proxies), strings.ToUpper(country), strings.ToUpper(country))
+	}
+	return matched, nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ProxyStat tracks the historical outcomes of registrations through one proxy
+type ProxyStat struct {
+	Server    string    `json:"server"`
+	Successes int       `json:"successes"`
+	Failures  int       `json:"failures"`
+	LastUsed  time.Time `json:"last_used"`
+}
+
+// Score estimates the proxy's success rate, smoothed so unseen proxies start at 0.5
+func (p *ProxyStat) Score() float64 {
+	return float64(p.Successes+1) / float64(p.Successes+p.Failures+2)
+}
+
+// ProxyStats persists per-proxy statistics across campaigns
+type ProxyStats struct {
+	path   string
+	stats  map[string]*ProxyStat
+	mu     sync.Mutex
+	saveMu sync.Mutex // Keeps concurrent saves from interleaving their writes
+}
+
+// sharedProxyStats holds one ProxyStats per file for the whole process, so
+// concurrent bot campaigns add to the same counts instead of each saving its
+// own copy over the others'
+var sharedProxyStats = struct {
+	byPath map[string]*ProxyStats
+	mu     sync.Mutex
+}{byPath: make(map[string]*ProxyStats)}
+
+// OpenProxyStats returns the process-wide stats for path, loading the file
+// on first use
+func OpenProxyStats(path string) (*ProxyStats, error) {
+	sharedProxyStats.mu.Lock()
+	defer sharedProxyStats.mu.Unlock()
+	if s, ok := sharedProxyStats.byPath[path]; ok {
+		return s, nil
+	}
+	s, err := LoadProxyStats(path)
+	if err != nil {
+		return nil, err
+	}
+	sharedProxyStats.byPath[path] = s
+	return s, nil
+}
+
+// proxyOutcome reports whether result says anything about its proxy and, if
+// so, whether the proxy worked. Only successes and failures a proxy can cause
+// count: a broken form, a captcha or an existing registration say nothing
+// about the proxy.
+func proxyOutcome(result RegistrationResult) (success, counted bool) {
+	if result.Status == "SUCCESS" {
+		return true, true
+	}
+	switch result.Category {
+	case CategoryProxyError, CategoryTimeout, CategoryDNS, CategoryNetwork:
+		return false, true
+	}
+	return false, false
+}
+
+// LoadProxyStats reads stats from path; a missing file yields empty stats
+func LoadProxyStats(path string) (*ProxyStats, error) {
+	s := &ProxyStats{path: path, stats: make(map[string]*ProxyStat)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading proxy stats: %v", err)
+	}
+
+	var list []*ProxyStat
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("invalid proxy stats file %s: %v", path, err)
+	}
+	for _, stat := range list {
+		s.stats[stat.Server] = stat
+	}
+	return s, nil
+}
+
+// Record adds one registration outcome for server
+func (s *ProxyStats) Record(server string, success bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stat, ok := s.stats[server]
+	if !ok {
+		stat = &ProxyStat{Server: server}
+		s.stats[server] = stat
+	}
+	if success {
+		stat.Successes++
+	} else {
+		stat.Failures++
+	}
+	stat.LastUsed = time.Now()
+}
+
+// Ranked returns a snapshot of all stats ordered by score, best first
+func (s *ProxyStats) Ranked() []ProxyStat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ranked := make([]ProxyStat, 0, len(s.stats))
+	for _, stat := range s.stats {
+		ranked = append(ranked, *stat)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score() != ranked[j].Score() {
+			return ranked[i].Score() > ranked[j].Score()
+		}
+		return ranked[i].Server < ranked[j].Server
+	})
+	return ranked
+}
+
+// SortProxies orders proxies by historical score so the most reliable are assigned first
+func (s *ProxyStats) SortProxies(proxies []ProxyConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	score := func(server string) float64 {
+		if stat, ok := s.stats[server]; ok {
+			return stat.Score()
+		}
+		return 0.5
+	}
+	sort.SliceStable(proxies, func(i, j int) bool {
+		return score(proxies[i].Server) > score(proxies[j].Server)
+	})
+}
+
+// Save writes the stats back to disk
+func (s *ProxyStats) Save() error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+	data, err := json.MarshalIndent(s.Ranked(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0644)
+}
+
+// formatProxyRanking renders the top n proxies as plain text lines
+func formatProxyRanking(ranked []ProxyStat, n int) []string {
+	var lines []string
+	for i, stat := range ranked {
+		if i >= n {
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%2d. %s  score %.0f%% (%d✓/%d✗)",
+			i+1, stat.Server, stat.Score()*100, stat.Successes, stat.Failures))
+	}
+	return lines
+}
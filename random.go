@@ -0,0 +1,62 @@
+package main
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// lockedRand is a *rand.Rand that is safe to share between workers
+type lockedRand struct {
+	r  *rand.Rand
+	mu sync.Mutex
+}
+
+// random feeds every randomized choice - task shuffle, retry jitter, user
+// agents, viewports, session IDs - so --seed makes a run repeatable
+var random = newLockedRand(time.Now().UnixNano())
+
+func newLockedRand(seed int64) *lockedRand {
+	return &lockedRand{r: rand.New(rand.NewSource(seed))}
+}
+
+// seedRandom reseeds random, from the clock when seed is 0, and returns the seed used
+func seedRandom(seed int64) int64 {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	random.mu.Lock()
+	defer random.mu.Unlock()
+	random.r = rand.New(rand.NewSource(seed))
+	return seed
+}
+
+func (l *lockedRand) Intn(n int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Intn(n)
+}
+
+func (l *lockedRand) Int63n(n int64) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Int63n(n)
+}
+
+func (l *lockedRand) Float64() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Float64()
+}
+
+func (l *lockedRand) Uint32() uint32 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Uint32()
+}
+
+func (l *lockedRand) Perm(n int) []int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Perm(n)
+}
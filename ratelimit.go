@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimiter spaces events evenly so at most perSecond start each second,
+// across every caller sharing it
+type RateLimiter struct {
+	interval time.Duration // 0 when unlimited
+	next     time.Time     // Earliest start of the next event
+	mu       sync.Mutex
+}
+
+// registrationRate throttles task starts across all campaigns, set by --rate-limit
+var registrationRate = NewRateLimiter(0)
+
+// proxyRates throttles attempts through each proxy, at --proxy-rate-limit
+var proxyRates = NewKeyedRateLimiter()
+
+// eventRates throttles attempts against each event host, at --event-rate-limit
+// or the template's rate_limit
+var eventRates = NewKeyedRateLimiter()
+
+// NewRateLimiter allows perSecond events per second; perSecond <= 0 means no limit
+func NewRateLimiter(perSecond float64) *RateLimiter {
+	l := &RateLimiter{}
+	if perSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / perSecond)
+	}
+	return l
+}
+
+// Wait blocks until the caller may start its event or ctx is done. A caller
+// that gives up hands its slot back, so the waiters a stopped campaign leaves
+// behind don't delay the next one.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	if l.interval == 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	start := time.Now()
+	if l.next.After(start) {
+		start = l.next
+	}
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(start)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.release()
+		return ctx.Err()
+	}
+}
+
+// release gives back one reserved slot. Later slots stay where they are, so
+// callers already waiting keep their start times; only new callers move up.
+func (l *RateLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.next = l.next.Add(-l.interval)
+	if now := time.Now(); l.next.Before(now) {
+		l.next = now
+	}
+}
+
+// Rate returns the allowed events per second, 0 if unlimited
+func (l *RateLimiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.interval == 0 {
+		return 0
+	}
+	return float64(time.Second) / float64(l.interval)
+}
+
+// setRate changes the allowed events per second; slots already handed out
+// keep their start times
+func (l *RateLimiter) setRate(perSecond float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.interval = 0
+	if perSecond > 0 {
+		l.interval = time.Duration(float64(time.Second) / perSecond)
+	}
+}
+
+// idle reports whether no slot is reserved past now, so the limiter would
+// behave exactly like a new one
+func (l *RateLimiter) idle(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return !l.next.After(now)
+}
+
+// keyedSweepInterval is how often a KeyedRateLimiter drops idle keys
+const keyedSweepInterval = time.Minute
+
+// KeyedRateLimiter keeps a separate RateLimiter for every key, such as a
+// proxy server or an event host
+type KeyedRateLimiter struct {
+	limiters map[string]*RateLimiter
+	swept    time.Time // Last time idle keys were dropped
+	mu       sync.Mutex
+}
+
+// NewKeyedRateLimiter creates an empty set of limiters
+func NewKeyedRateLimiter() *KeyedRateLimiter {
+	return &KeyedRateLimiter{limiters: make(map[string]*RateLimiter), swept: time.Now()}
+}
+
+// Wait blocks until an event for key may start or ctx is done, at perSecond
+// events per second for key; perSecond <= 0 means no limit. A new rate for
+// a key takes over from the old one, e.g. when a later campaign runs the
+// same host with a different template.
+func (k *KeyedRateLimiter) Wait(ctx context.Context, key string, perSecond float64) error {
+	if perSecond <= 0 {
+		return nil
+	}
+
+	k.mu.Lock()
+	now := time.Now()
+	if now.Sub(k.swept) >= keyedSweepInterval {
+		k.sweep(now)
+	}
+	limiter, ok := k.limiters[key]
+	if !ok {
+		limiter = NewRateLimiter(perSecond)
+		k.limiters[key] = limiter
+	} else {
+		limiter.setRate(perSecond)
+	}
+	k.mu.Unlock()
+	return limiter.Wait(ctx)
+}
+
+// sweep drops the limiters of keys with nothing reserved, so proxies and
+// hosts from finished campaigns don't pile up. k.mu must be held.
+func (k *KeyedRateLimiter) sweep(now time.Time) {
+	for key, limiter := range k.limiters {
+		if limiter.idle(now) {
+			delete(k.limiters, key)
+		}
+	}
+	k.swept = now
+}
+
+// eventHost returns the lower-case host of eventURL, the key for eventRates
+func eventHost(eventURL string) string {
+	u, err := url.Parse(eventURL)
+	if err != nil || u.Hostname() == "" {
+		return eventURL
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// effectiveRateLimit is the registrations per second the limits allow with
+// the given number of proxies, 0 if unlimited
+func effectiveRateLimit(proxies int) float64 {
+	rate := registrationRate.Rate()
+	if config.ProxyRateLimit > 0 && proxies > 0 {
+		perProxy := config.ProxyRateLimit * float64(proxies)
+		if rate == 0 {
+			return perProxy
+		}
+		rate = math.Min(rate, perProxy)
+	}
+	return rate
+}
+
+// formatRate renders a registrations-per-second limit
+func formatRate(rate float64) string {
+	if rate <= 0 {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%.1f/sec", rate)
+}
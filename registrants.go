@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// RegistrantError describes a validation failure on one line of the registrants file
+type RegistrantError struct {
+	Line    int
+	Message string
+}
+
+// RegistrantValidationError collects every invalid row found in strict mode
+type RegistrantValidationError []RegistrantError
+
+func (e RegistrantValidationError) Error() string {
+	lines := make([]string, 0, len(e))
+	for _, err := range e {
+		lines = append(lines, fmt.Sprintf("line %d: %s", err.Line, err.Message))
+	}
+	return fmt.Sprintf("%d invalid registrant row(s):\n  %s", len(e), strings.Join(lines, "\n  "))
+}
+
+// contactColumns maps accepted header names to Contact fields
+var contactColumns = map[string]string{
+	"email":        "email",
+	"e-mail":       "email",
+	"first_name":   "first_name",
+	"firstname":    "first_name",
+	"first name":   "first_name",
+	"last_name":    "last_name",
+	"lastname":     "last_name",
+	"last name":    "last_name",
+	"organization": "organization",
+	"organisation": "organization",
+	"org":          "organization",
+	"company":      "organization",
+}
+
+// readRegistrants loads registrants from a CSV file with a header row. Blank
+// fields fall back to defaults. In strict mode every invalid row is reported
+// together with its line number; otherwise invalid rows are skipped and counted.
+func readRegistrants(filename string, defaults Contact, strict bool, logger *Logger) ([]Contact, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("registrants file not found: %s", filename)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading registrants header: %v", err)
+	}
+
+	columns := make(map[string]int)
+	extra := make(map[int]string) // Other columns, available to custom form fields
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if field, ok := contactColumns[name]; ok {
+			columns[field] = i
+		} else if name != "" {
+			extra[i] = name
+		}
+	}
+	if _, ok := columns["email"]; !ok {
+		return nil, fmt.Errorf("registrants file %s has no email column", filename)
+	}
+
+	get := func(record []string, field string) string {
+		if i, ok := columns[field]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	orDefault := func(value, fallback string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	}
+
+	var registrants []Contact
+	var invalid RegistrantValidationError
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			invalid = append(invalid, RegistrantError{Line: line, Message: err.Error()})
+			continue
+		}
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		r := Contact{
+			Email:        get(record, "email"),
+			FirstName:    orDefault(get(record, "first_name"), defaults.FirstName),
+			LastName:     orDefault(get(record, "last_name"), defaults.LastName),
+			Organization: orDefault(get(record, "organization"), defaults.Organization),
+		}
+		for i, name := range extra {
+			if i < len(record) && strings.TrimSpace(record[i]) != "" {
+				if r.Fields == nil {
+					r.Fields = make(map[string]string)
+				}
+				r.Fields[name] = strings.TrimSpace(record[i])
+			}
+		}
+
+		var problems []string
+		if err := validateEmail(r.Email); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid email %q: %v", r.Email, err))
+		}
+		if r.FirstName == "" {
+			problems = append(problems, "missing first name")
+		}
+		if r.LastName == "" {
+			problems = append(problems, "missing last name")
+		}
+		if r.Organization == "" {
+			problems = append(problems, "missing organization")
+		}
+		if len(problems) > 0 {
+			invalid = append(invalid, RegistrantError{Line: line, Message: strings.Join(problems, ", ")})
+			continue
+		}
+
+		registrants = append(registrants, r)
+		logger.Debug("Loaded registrant: %s", r.Email)
+	}
+
+	if len(invalid) > 0 {
+		if strict {
+			return nil, invalid
+		}
+		logger.Warning("Skipped %d invalid registrant row(s) in %s", len(invalid), filename)
+		for _, e := range invalid {
+			logger.Debug("  line %d: %s", e.Line, e.Message)
+		}
+	}
+
+	logger.Info("Loaded %d registrants from %s", len(registrants), filename)
+	return registrants, nil
+}
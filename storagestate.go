@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// unsafeFileChars are replaced when an event host becomes a file name
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9.-]`)
+
+// storageStatePath is where cookies for eventURL's host are kept, or "" when
+// storage reuse is off
+func storageStatePath(eventURL string) string {
+	if config.StorageDir == "" {
+		return ""
+	}
+	return filepath.Join(config.StorageDir, unsafeFileChars.ReplaceAllString(eventHost(eventURL), "_")+".json")
+}
+
+// savedStorageState returns the saved state for eventURL's host if there is one
+func savedStorageState(eventURL string) (string, bool) {
+	path := storageStatePath(eventURL)
+	if path == "" {
+		return "", false
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// reusableCookie reports whether c can be handed to the next registrant.
+// Consent and preference cookies are set by page scripts and outlive the
+// session; session cookies and HttpOnly ones identify the registrant (login,
+// CSRF, tracking IDs) and would tie every registration on the host together.
+func reusableCookie(c playwright.Cookie) bool {
+	return c.Expires > 0 && !c.HttpOnly
+}
+
+// saveStorageState keeps the reusable cookies of browserContext for later
+// registrations on eventURL's host. Local storage is left out: it is where
+// forms keep the last registrant's details, and the next registrant mustn't
+// inherit them.
+func saveStorageState(browserContext playwright.BrowserContext, eventURL string) error {
+	path := storageStatePath(eventURL)
+	if path == "" {
+		return nil
+	}
+
+	state, err := browserContext.StorageState()
+	if err != nil {
+		return err
+	}
+	state.Origins = []playwright.Origin{}
+	cookies := []playwright.Cookie{}
+	for _, cookie := range state.Cookies {
+		if reusableCookie(cookie) {
+			cookies = append(cookies, cookie)
+		}
+	}
+	if len(cookies) == 0 {
+		return nil
+	}
+	state.Cookies = cookies
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(config.StorageDir, 0755); err != nil {
+		return err
+	}
+	// Workers finishing the same event at once each write their own temp file
+	tmp, err := os.CreateTemp(config.StorageDir, ".state-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -1,11 +1,18 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -18,11 +25,31 @@ type TelegramBot struct {
 	apiURL       string
 	lastUpdateID int64
 	logger       *Logger
-	campaign     *CampaignManager
+	campaigns    map[int64]*CampaignManager
 	userConfigs  map[int64]*UserConfig
+	queues       map[int64]chan *TelegramUpdate
+	access       *ChatAllowlist
+	lastActivity time.Time
 	mu           sync.Mutex
+
+	// newWorker, when set, replaces the browser workers of every campaign
+	newWorker func(workerID int, proxyPool *ProxyPool) Worker
 }
 
+// chatQueueSize is the number of pending messages buffered per chat
+const chatQueueSize = 100
+
+const (
+	// chatSendInterval keeps per-chat sends under Telegram's ~1 msg/sec limit
+	chatSendInterval = time.Second
+	// maxSendRetries bounds resends after HTTP 429 responses
+	maxSendRetries = 3
+	// telegramMessageLimit is the most characters Telegram accepts in one message
+	telegramMessageLimit = 4096
+	// defaultLogLines is how many lines /logs sends without a count
+	defaultLogLines = 50
+)
+
 // UserConfig stores per-user configuration
 type UserConfig struct {
 	FirstName    string
@@ -31,15 +58,24 @@ type UserConfig struct {
 	EmailsFile   string
 	EventsFile   string
 	ProxiesFile  string
+	Profile      string
+	Webhook      string
 	MaxWorkers   int
+	Retries      int
+	Quiet        bool // Don't push progress updates during campaigns
 	State        string
+	PendingLines []string // Input collected in the current state, kept until it completes
 	mu           sync.Mutex
 }
 
 // CampaignManager manages ongoing campaigns
 type CampaignManager struct {
 	running       bool
+	paused        bool
+	dryRun        bool
 	orchestrator  *RegistrationOrchestrator
+	cancel        context.CancelFunc
+	testCancel    context.CancelFunc // Set while a /test registration runs
 	results       []RegistrationResult
 	startTime     time.Time
 	mu            sync.Mutex
@@ -47,8 +83,39 @@ type CampaignManager struct {
 
 // TelegramUpdate represents a Telegram API update
 type TelegramUpdate struct {
-	UpdateID int64            `json:"update_id"`
-	Message  *TelegramMessage `json:"message"`
+	UpdateID      int64                  `json:"update_id"`
+	Message       *TelegramMessage       `json:"message"`
+	CallbackQuery *TelegramCallbackQuery `json:"callback_query"`
+}
+
+// TelegramCallbackQuery is sent when a user taps an inline keyboard button
+type TelegramCallbackQuery struct {
+	ID      string           `json:"id"`
+	From    *TelegramUser    `json:"from"`
+	Message *TelegramMessage `json:"message"`
+	Data    string           `json:"data"`
+}
+
+// InlineKeyboardButton is a button under a message; CallbackData comes back
+// in a callback query when tapped
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// buttonRow builds a keyboard row from alternating label and callback data
+// arguments: buttonRow("✅ Yes", "register:confirm", "❌ No", "register:cancel")
+func buttonRow(labelsAndData ...string) []InlineKeyboardButton {
+	row := make([]InlineKeyboardButton, 0, len(labelsAndData)/2)
+	for i := 0; i+1 < len(labelsAndData); i += 2 {
+		row = append(row, InlineKeyboardButton{Text: labelsAndData[i], CallbackData: labelsAndData[i+1]})
+	}
+	return row
+}
+
+// replyMarkup wraps keyboard rows as a sendMessage reply_markup
+func replyMarkup(rows [][]InlineKeyboardButton) map[string]interface{} {
+	return map[string]interface{}{"inline_keyboard": rows}
 }
 
 // TelegramMessage represents a Telegram message
@@ -95,9 +162,38 @@ func NewTelegramBot(token string, logger *Logger) *TelegramBot {
 		token:       token,
 		apiURL:      fmt.Sprintf("https://api.telegram.org/bot%s", token),
 		logger:      logger,
-		campaign:    &CampaignManager{},
+		campaigns:   make(map[int64]*CampaignManager),
 		userConfigs: make(map[int64]*UserConfig),
+		queues:      make(map[int64]chan *TelegramUpdate),
+		access:      NewChatAllowlist(config.AdminChat, config.AllowedChats),
+	}
+}
+
+// getCampaign returns chatID's campaign, creating an idle one on first use
+func (b *TelegramBot) getCampaign(chatID int64) *CampaignManager {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, exists := b.campaigns[chatID]; !exists {
+		b.campaigns[chatID] = &CampaignManager{}
+	}
+	return b.campaigns[chatID]
+}
+
+// fingerprint summarizes the settings and files a campaign would start
+// with, so a confirmation can tell they changed. u.mu must be held.
+func (u *UserConfig) fingerprint() string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s|%s|%s|%s|%s|%s|%d|%d", u.FirstName, u.LastName, u.Organization,
+		u.ProxiesFile, u.Profile, u.Webhook, u.MaxWorkers, u.Retries)
+	// Uploads replace the files in place, so their size and time count too
+	for _, path := range []string{u.EmailsFile, u.EventsFile} {
+		fmt.Fprintf(hash, "|%s", path)
+		if info, err := os.Stat(path); err == nil {
+			fmt.Fprintf(hash, ":%d:%d", info.Size(), info.ModTime().UnixNano())
+		}
 	}
+	return hex.EncodeToString(hash.Sum(nil)[:8])
 }
 
 // getUserConfig gets or creates user config
@@ -110,19 +206,32 @@ func (b *TelegramBot) getUserConfig(chatID int64) *UserConfig {
 			EmailsFile:  fmt.Sprintf("emails_%d.txt", chatID),
 			EventsFile:  fmt.Sprintf("events_%d.txt", chatID),
 			ProxiesFile: "proxies.txt",
+			Profile:     defaultTemplateName,
+			Webhook:     config.WebhookURL,
 			MaxWorkers:  20, // Default
+			Retries:     config.RegistrationRetry,
 			State:       "idle",
 		}
 	}
 	return b.userConfigs[chatID]
 }
 
-// Start begins polling for Telegram updates
+// Start begins polling for Telegram updates. It returns only when the
+// idle shutdown timeout is reached.
 func (b *TelegramBot) Start() {
 	b.logger.Info("🤖 Telegram Bot started - waiting for commands...")
 	b.logger.Info("Send /help to see available commands")
+	if config.IdleShutdown > 0 {
+		b.logger.Info("Idle shutdown after %v without activity", config.IdleShutdown)
+	}
+	b.lastActivity = time.Now()
 
 	for {
+		if b.idleExpired() {
+			b.logger.Info("💤 Shutting down: no messages or campaigns for %v", config.IdleShutdown)
+			return
+		}
+
 		updates, err := b.getUpdates()
 		if err != nil {
 			b.logger.Error("Failed to get updates: %v", err)
@@ -131,8 +240,13 @@ func (b *TelegramBot) Start() {
 		}
 
 		for _, update := range updates {
-			if update.Message != nil {
-				b.handleMessage(update.Message)
+			switch {
+			case update.Message != nil:
+				b.lastActivity = time.Now()
+				b.enqueueUpdate(update.Message.Chat.ID, update)
+			case update.CallbackQuery != nil && update.CallbackQuery.Message != nil:
+				b.lastActivity = time.Now()
+				b.enqueueUpdate(update.CallbackQuery.Message.Chat.ID, update)
 			}
 			b.lastUpdateID = update.UpdateID + 1
 		}
@@ -141,6 +255,142 @@ func (b *TelegramBot) Start() {
 	}
 }
 
+// anyRunning reports whether any chat has a campaign running
+func (b *TelegramBot) anyRunning() bool {
+	b.mu.Lock()
+	campaigns := make([]*CampaignManager, 0, len(b.campaigns))
+	for _, campaign := range b.campaigns {
+		campaigns = append(campaigns, campaign)
+	}
+	b.mu.Unlock()
+
+	for _, campaign := range campaigns {
+		campaign.mu.Lock()
+		running := campaign.running
+		campaign.mu.Unlock()
+		if running {
+			return true
+		}
+	}
+	return false
+}
+
+// idleExpired reports whether the bot has been idle longer than --idle-shutdown
+func (b *TelegramBot) idleExpired() bool {
+	if config.IdleShutdown <= 0 {
+		return false
+	}
+
+	if b.anyRunning() {
+		b.lastActivity = time.Now()
+		return false
+	}
+	return time.Since(b.lastActivity) > config.IdleShutdown
+}
+
+// enqueueUpdate hands an update to its chat's queue so slow commands for one
+// chat never delay others, while preserving per-chat ordering
+func (b *TelegramBot) enqueueUpdate(chatID int64, update TelegramUpdate) {
+	b.mu.Lock()
+	queue, exists := b.queues[chatID]
+	if !exists {
+		queue = make(chan *TelegramUpdate, chatQueueSize)
+		b.queues[chatID] = queue
+		go b.processQueue(queue)
+	}
+	b.mu.Unlock()
+
+	select {
+	case queue <- &update:
+	default:
+		b.logger.Warning("Command queue full for chat %d - dropping message", chatID)
+	}
+}
+
+// processQueue handles a single chat's messages and button taps in order
+func (b *TelegramBot) processQueue(queue chan *TelegramUpdate) {
+	for update := range queue {
+		if update.CallbackQuery != nil {
+			b.handleCallback(update.CallbackQuery)
+		} else {
+			b.handleMessage(update.Message)
+		}
+	}
+}
+
+// handleCallback acknowledges a button tap and routes its data. Data starting
+// with "/" runs as a typed command; "action:arg" data goes to an action handler.
+func (b *TelegramBot) handleCallback(query *TelegramCallbackQuery) {
+	chatID := query.Message.Chat.ID
+	if !b.access.Allowed(chatID) {
+		b.logger.Warning("🔒 Denied button %q from unauthorized chat %d", query.Data, chatID)
+		b.answerCallbackQuery(query.ID, "Not authorized")
+		return
+	}
+
+	if strings.HasPrefix(query.Data, "/") {
+		b.answerCallbackQuery(query.ID, "")
+		b.handleMessage(&TelegramMessage{
+			MessageID: query.Message.MessageID,
+			From:      query.From,
+			Chat:      query.Message.Chat,
+			Text:      query.Data,
+		})
+		return
+	}
+
+	action, arg, _ := strings.Cut(query.Data, ":")
+	switch action {
+	case "register":
+		b.handleRegisterCallback(chatID, query.ID, arg)
+	default:
+		b.answerCallbackQuery(query.ID, "Unknown action")
+		b.logger.Warning("Ignoring unknown callback data: %q", query.Data)
+	}
+}
+
+// handleRegisterCallback starts or cancels a campaign from the /register
+// confirmation. Confirm carries the fingerprint of the settings it showed, so
+// an old button can't start a campaign with settings changed since.
+func (b *TelegramBot) handleRegisterCallback(chatID int64, queryID, arg string) {
+	arg, reviewed, _ := strings.Cut(arg, ":")
+	switch arg {
+	case "confirm":
+		userConfig := b.getUserConfig(chatID)
+		userConfig.mu.Lock()
+		current := userConfig.fingerprint()
+		userConfig.mu.Unlock()
+		if reviewed != current {
+			b.answerCallbackQuery(queryID, "Settings changed")
+			b.sendMessage(chatID, "⚠️ Your settings or files changed since this confirmation\n\nSend /register to review them again")
+			return
+		}
+		b.answerCallbackQuery(queryID, "Starting campaign...")
+		b.startCampaign(chatID, userConfig, false)
+	case "cancel":
+		b.answerCallbackQuery(queryID, "Cancelled")
+		b.sendMessage(chatID, "🚫 Campaign not started")
+	default:
+		b.answerCallbackQuery(queryID, "Unknown action")
+	}
+}
+
+// answerCallbackQuery stops the button's loading spinner, optionally showing text
+func (b *TelegramBot) answerCallbackQuery(queryID, text string) {
+	payload := map[string]interface{}{"callback_query_id": queryID}
+	if text != "" {
+		payload["text"] = text
+	}
+	jsonData, _ := json.Marshal(payload)
+
+	resp, err := http.Post(fmt.Sprintf("%s/answerCallbackQuery", b.apiURL), "application/json", bytes.NewReader(jsonData))
+	if err != nil {
+		b.logger.Error("Failed to answer callback query: %v", err)
+		return
+	}
+	resp.Body.Close()
+}
+
 // getUpdates fetches new updates from Telegram
 func (b *TelegramBot) getUpdates() ([]TelegramUpdate, error) {
 	url := fmt.Sprintf("%s/getUpdates?offset=%d&timeout=30", b.apiURL, b.lastUpdateID)
@@ -171,6 +421,10 @@ func (b *TelegramBot) getUpdates() ([]TelegramUpdate, error) {
 // handleMessage processes incoming messages
 func (b *TelegramBot) handleMessage(msg *TelegramMessage) {
 	chatID := msg.Chat.ID
+	if !b.access.Allowed(chatID) {
+		b.denyChat(msg)
+		return
+	}
 	userConfig := b.getUserConfig(chatID)
 
 	// Handle file uploads
@@ -198,27 +452,122 @@ func (b *TelegramBot) handleMessage(msg *TelegramMessage) {
 		b.sendWelcome(chatID)
 	case text == "/help":
 		b.sendHelp(chatID)
-	case text == "/setup":
-		b.handleSetup(chatID, userConfig)
+	case text == "/setup" || strings.HasPrefix(text, "/setup "):
+		b.handleSetup(chatID, text, userConfig)
 	case strings.HasPrefix(text, "/workers"):
 		b.handleWorkers(chatID, text, userConfig)
+	case strings.HasPrefix(text, "/retries"):
+		b.handleRetries(chatID, text, userConfig)
 	case text == "/status":
 		b.sendStatus(chatID)
+	case text == "/quiet" || strings.HasPrefix(text, "/quiet "):
+		b.handleQuiet(chatID, text, userConfig)
+	case text == "/cancel":
+		b.sendMessage(chatID, "ℹ️ Nothing to cancel")
+	case text == "/emails":
+		b.handleInlineList(chatID, "awaiting_emails", "emails", userConfig)
+	case text == "/events":
+		b.handleInlineList(chatID, "awaiting_events", "event URLs", userConfig)
 	case text == "/register":
 		b.handleRegister(chatID, userConfig)
+	case text == "/dryrun":
+		b.startCampaign(chatID, userConfig, true)
+	case text == "/test" || strings.HasPrefix(text, "/test "):
+		b.handleTest(chatID, text, userConfig)
+	case text == "/pause":
+		b.handlePause(chatID)
+	case text == "/resume":
+		b.handleResume(chatID)
 	case text == "/stop":
 		b.handleStop(chatID)
-	case text == "/results":
-		b.sendResults(chatID)
+	case strings.HasPrefix(text, "/results"):
+		b.sendResults(chatID, text)
+	case text == "/download":
+		b.handleDownload(chatID)
 	case text == "/stats":
 		b.sendStats(chatID)
+	case strings.HasPrefix(text, "/profile"):
+		b.handleProfile(chatID, text, userConfig)
+	case strings.HasPrefix(text, "/webhook"):
+		b.handleWebhook(chatID, text, userConfig)
+	case text == "/proxystats":
+		b.sendProxyStats(chatID)
+	case text == "/version":
+		b.sendMessage(chatID, fmt.Sprintf("ℹ️ <code>%s</code>", versionString()))
 	case text == "/config":
 		b.handleConfig(chatID, userConfig)
+	case text == "/logs" || strings.HasPrefix(text, "/logs "):
+		b.sendLogs(chatID, text)
+	case text == "/verbose" || strings.HasPrefix(text, "/verbose "):
+		b.handleVerbose(chatID, text)
+	case text == "/allow" || strings.HasPrefix(text, "/allow ") || text == "/deny" || strings.HasPrefix(text, "/deny "):
+		b.handleAccess(chatID, text)
 	default:
 		b.sendMessage(chatID, "❌ Unknown command. Send /help for available commands.")
 	}
 }
 
+// denyChat turns away a chat that isn't on the allowlist
+func (b *TelegramBot) denyChat(msg *TelegramMessage) {
+	username := ""
+	if msg.From != nil {
+		username = msg.From.Username
+	}
+	b.logger.Warning("🔒 Denied unauthorized chat %d (@%s): %s", msg.Chat.ID, username, truncateString(msg.Text, 50))
+	b.sendMessage(msg.Chat.ID, fmt.Sprintf(
+		"🔒 Sorry, this bot is private.\n\nYour chat ID is <code>%d</code> - ask the bot's admin to add it.", msg.Chat.ID,
+	))
+}
+
+// handleAccess lets the admin list, allow and deny chats. Changes last until
+// restart; chats in ALLOWED_CHAT_IDS are allowed on every start.
+func (b *TelegramBot) handleAccess(chatID int64, text string) {
+	if !b.access.IsAdmin(chatID) {
+		b.sendMessage(chatID, "❌ Only the bot's admin can change who may use it")
+		return
+	}
+
+	parts := strings.Fields(text)
+	if len(parts) == 1 && parts[0] == "/allow" {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "<b>🔒 Allowed Chats</b>\n\n• <code>%d</code> (admin)\n", chatID)
+		for _, id := range b.access.Chats() {
+			fmt.Fprintf(&sb, "• <code>%d</code>\n", id)
+		}
+		sb.WriteString("\n<b>Usage:</b> /allow &lt;chat ID&gt; or /deny &lt;chat ID&gt;")
+		b.sendMessage(chatID, sb.String())
+		return
+	}
+
+	ids, err := parseChatIDs(strings.Join(parts[1:], ","))
+	if len(parts) != 2 || err != nil || len(ids) != 1 {
+		b.sendMessage(chatID, fmt.Sprintf("❌ Usage: %s &lt;chat ID&gt;", parts[0]))
+		return
+	}
+	target := ids[0]
+
+	if parts[0] == "/allow" {
+		if !b.access.Allow(target) {
+			b.sendMessage(chatID, fmt.Sprintf("ℹ️ Chat <code>%d</code> is already allowed", target))
+			return
+		}
+		b.logger.Info("🔓 Admin allowed chat %d", target)
+		b.sendMessage(chatID, fmt.Sprintf("✅ Chat <code>%d</code> may now use the bot (until restart - add it to %s to keep it)", target, allowedChatsEnv))
+		return
+	}
+
+	if b.access.IsAdmin(target) {
+		b.sendMessage(chatID, "❌ The admin chat can't be denied")
+		return
+	}
+	if !b.access.Deny(target) {
+		b.sendMessage(chatID, fmt.Sprintf("ℹ️ Chat <code>%d</code> wasn't allowed", target))
+		return
+	}
+	b.logger.Info("🔒 Admin denied chat %d", target)
+	b.sendMessage(chatID, fmt.Sprintf("✅ Chat <code>%d</code> can no longer use the bot", target))
+}
+
 // handleWorkers sets max worker count
 func (b *TelegramBot) handleWorkers(chatID int64, text string, userConfig *UserConfig) {
 	parts := strings.Fields(text)
@@ -239,10 +588,13 @@ func (b *TelegramBot) handleWorkers(chatID int64, text string, userConfig *UserC
 				"• Medium: 20-50 workers (balanced)\n"+
 				"• High: 50-100 workers (fast, requires good server)\n"+
 				"• Extreme: 100+ workers (Hetzner dedicated only)\n\n"+
-				"<b>Note:</b> Each worker runs 1 browser instance",
+				"<b>Note:</b> Each worker runs 1 browser instance\n\n"+
+				"Tap a preset or send a custom number:",
 			current,
 		)
-		b.sendMessage(chatID, msg)
+		b.sendMessageWithKeyboard(chatID, msg, [][]InlineKeyboardButton{
+			buttonRow("10", "/workers 10", "20", "/workers 20", "50", "/workers 50", "100", "/workers 100"),
+		})
 		return
 	}
 
@@ -252,8 +604,8 @@ func (b *TelegramBot) handleWorkers(chatID int64, text string, userConfig *UserC
 	}
 
 	workers, err := strconv.Atoi(parts[1])
-	if err != nil || workers < 1 || workers > 200 {
-		b.sendMessage(chatID, "❌ Please provide a number between 1 and 200")
+	if err != nil || workers < 1 || workers > maxCampaignWorkers {
+		b.sendMessage(chatID, fmt.Sprintf("❌ Please provide a number between 1 and %d", maxCampaignWorkers))
 		return
 	}
 
@@ -261,6 +613,21 @@ func (b *TelegramBot) handleWorkers(chatID int64, text string, userConfig *UserC
 	userConfig.MaxWorkers = workers
 	userConfig.mu.Unlock()
 
+	// A running campaign picks up the new count without restarting
+	campaign := b.getCampaign(chatID)
+	campaign.mu.Lock()
+	orchestrator := campaign.orchestrator
+	campaign.mu.Unlock()
+	running := ""
+	if orchestrator != nil {
+		if previous, ok := orchestrator.SetWorkers(workers); ok {
+			running = fmt.Sprintf("\n🔄 Running campaign: %d → %d workers", previous, workers)
+			if workers < previous {
+				running += " (extra workers stop after their current registration)"
+			}
+		}
+	}
+
 	var recommendation string
 	if workers <= 20 {
 		recommendation = "✅ Safe for most servers"
@@ -279,12 +646,126 @@ func (b *TelegramBot) handleWorkers(chatID int64, text string, userConfig *UserC
 			"<b>Performance Estimate:</b>\n"+
 			"• ~%d simultaneous registrations\n"+
 			"• ~%.1f GB RAM usage\n"+
-			"• ~%d Mbps bandwidth needed",
-		workers, recommendation, workers, float64(workers)*0.15, workers*2,
+			"• ~%d Mbps bandwidth needed%s",
+		workers, recommendation, workers, float64(workers)*0.15, workers*2, running,
 	)
 	b.sendMessage(chatID, msg)
 }
 
+// handleProfile lists form profiles or sets the active one
+func (b *TelegramBot) handleProfile(chatID int64, text string, userConfig *UserConfig) {
+	parts := strings.Fields(text)
+
+	if len(parts) == 1 {
+		userConfig.mu.Lock()
+		current := userConfig.Profile
+		userConfig.mu.Unlock()
+
+		msg := "<b>📋 Form Profiles</b>\n\n"
+		for _, name := range formTemplateNames() {
+			profile, _ := getFormTemplate(name)
+			marker := "•"
+			if name == current {
+				marker = "✅"
+			}
+			msg += fmt.Sprintf("%s <code>%s</code> - %s\n", marker, htmlEscape(name), htmlEscape(profile.Description))
+		}
+		msg += "\n<b>Usage:</b> /profile &lt;name&gt;"
+		b.sendMessage(chatID, msg)
+		return
+	}
+
+	if len(parts) != 2 {
+		b.sendMessage(chatID, "❌ Usage: /profile &lt;name&gt;\nSend /profile to list available profiles")
+		return
+	}
+
+	profile, ok := getFormTemplate(parts[1])
+	if !ok {
+		b.sendMessage(chatID, fmt.Sprintf("❌ Unknown profile <code>%s</code>\n\nSend /profile to list available profiles", htmlEscape(parts[1])))
+		return
+	}
+
+	userConfig.mu.Lock()
+	userConfig.Profile = profile.Name
+	userConfig.mu.Unlock()
+
+	b.sendMessage(chatID, fmt.Sprintf("✅ Profile set to <b>%s</b>\n\nIt will be used on your next /register", htmlEscape(profile.Name)))
+}
+
+// handleRetries sets how many attempts each registration gets
+func (b *TelegramBot) handleRetries(chatID int64, text string, userConfig *UserConfig) {
+	parts := strings.Fields(text)
+
+	if len(parts) == 1 {
+		userConfig.mu.Lock()
+		current := userConfig.Retries
+		userConfig.mu.Unlock()
+
+		b.sendMessage(chatID, fmt.Sprintf(
+			"<b>🔄 Retry Configuration</b>\n\n"+
+				"Current: <b>%d attempts</b> per registration\n\n"+
+				"<b>Usage:</b> /retries &lt;number&gt; (%d-%d)\n"+
+				"Example: <code>/retries 5</code>",
+			current, minRegistrationRetries, maxRegistrationRetries,
+		))
+		return
+	}
+
+	retries, err := strconv.Atoi(parts[1])
+	if len(parts) != 2 || err != nil || retries < minRegistrationRetries || retries > maxRegistrationRetries {
+		b.sendMessage(chatID, fmt.Sprintf("❌ Please provide a number between %d and %d", minRegistrationRetries, maxRegistrationRetries))
+		return
+	}
+
+	userConfig.mu.Lock()
+	userConfig.Retries = retries
+	userConfig.mu.Unlock()
+
+	b.sendMessage(chatID, fmt.Sprintf("✅ Retries set to <b>%d</b>\n\nIt will be used on your next /register", retries))
+}
+
+// handleWebhook shows or sets the URL that receives campaign results
+func (b *TelegramBot) handleWebhook(chatID int64, text string, userConfig *UserConfig) {
+	parts := strings.Fields(text)
+
+	if len(parts) == 1 {
+		userConfig.mu.Lock()
+		current := userConfig.Webhook
+		userConfig.mu.Unlock()
+
+		if current == "" {
+			current = "not set"
+		}
+		b.sendMessage(chatID, fmt.Sprintf(
+			"<b>🔗 Webhook</b>\n\n"+
+				"Current: <code>%s</code>\n\n"+
+				"<b>Usage:</b> /webhook &lt;url&gt; or /webhook off\n"+
+				"Results are POSTed as JSON when a campaign finishes",
+			htmlEscape(current),
+		))
+		return
+	}
+
+	webhookURL := parts[1]
+	if strings.EqualFold(webhookURL, "off") {
+		webhookURL = ""
+	} else if u, err := url.Parse(webhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		b.sendMessage(chatID, "❌ Invalid URL\n\nExample: <code>/webhook https://hooks.example.com/campaign</code>")
+		return
+	}
+
+	userConfig.mu.Lock()
+	userConfig.Webhook = webhookURL
+	userConfig.mu.Unlock()
+
+	if webhookURL == "" {
+		b.sendMessage(chatID, "✅ Webhook disabled")
+		return
+	}
+	b.sendMessage(chatID, fmt.Sprintf("✅ Webhook set to <code>%s</code>", htmlEscape(webhookURL)))
+}
+
 // handleFileUpload processes file uploads
 func (b *TelegramBot) handleFileUpload(chatID int64, doc *TelegramDocument, userConfig *UserConfig) {
 	fileName := strings.ToLower(doc.FileName)
@@ -294,6 +775,13 @@ func (b *TelegramBot) handleFileUpload(chatID int64, doc *TelegramDocument, user
 	if strings.Contains(fileName, "email") {
 		targetFile = userConfig.EmailsFile
 		fileType = "emails"
+		// Keep the extension so CSV contact lists are read with their name columns
+		if ext := filepath.Ext(fileName); isCSVFile(fileName) != isCSVFile(targetFile) {
+			targetFile = strings.TrimSuffix(targetFile, filepath.Ext(targetFile)) + ext
+			userConfig.mu.Lock()
+			userConfig.EmailsFile = targetFile
+			userConfig.mu.Unlock()
+		}
 	} else if strings.Contains(fileName, "event") || strings.Contains(fileName, "list") {
 		targetFile = userConfig.EventsFile
 		fileType = "events"
@@ -306,11 +794,11 @@ func (b *TelegramBot) handleFileUpload(chatID int64, doc *TelegramDocument, user
 	}
 
 	if err := b.downloadFile(doc.FileID, targetFile); err != nil {
-		b.sendMessage(chatID, fmt.Sprintf("❌ Failed to download file: %v", err))
+		b.sendMessage(chatID, fmt.Sprintf("❌ Failed to download file: %s", htmlEscape(err)))
 		return
 	}
 
-	b.sendMessage(chatID, fmt.Sprintf("✅ %s file uploaded successfully!\n\nFile: <code>%s</code>", strings.Title(fileType), targetFile))
+	b.sendMessage(chatID, fmt.Sprintf("✅ %s file uploaded successfully!\n\nFile: <code>%s</code>", strings.Title(fileType), htmlEscape(targetFile)))
 	b.logger.Info("File uploaded for chat %d: %s -> %s", chatID, doc.FileName, targetFile)
 }
 
@@ -349,37 +837,205 @@ func (b *TelegramBot) downloadFile(fileID, targetPath string) error {
 	return err
 }
 
-// handleSetup starts the setup wizard
-func (b *TelegramBot) handleSetup(chatID int64, userConfig *UserConfig) {
+// parseSetupArgs splits the one-shot "/setup First|Last|Organization" form
+func parseSetupArgs(args string) (firstName, lastName, organization string, err error) {
+	fields := strings.Split(args, "|")
+	if len(fields) != 3 {
+		return "", "", "", fmt.Errorf("expected FirstName|LastName|Organization")
+	}
+	for i, field := range fields {
+		fields[i] = strings.TrimSpace(field)
+		if fields[i] == "" {
+			return "", "", "", fmt.Errorf("first name, last name and organization must not be empty")
+		}
+	}
+	return fields[0], fields[1], fields[2], nil
+}
+
+// handleSetup configures the registration identity in one message when given
+// arguments, otherwise starts the setup wizard
+func (b *TelegramBot) handleSetup(chatID int64, text string, userConfig *UserConfig) {
+	if args := strings.TrimSpace(strings.TrimPrefix(text, "/setup")); args != "" {
+		firstName, lastName, organization, err := parseSetupArgs(args)
+		if err != nil {
+			b.sendMessage(chatID, fmt.Sprintf(
+				"❌ %s\n\n<b>Usage:</b> <code>/setup First|Last|Organization</code>\nor send /setup alone for the wizard", htmlEscape(err),
+			))
+			return
+		}
+
+		userConfig.mu.Lock()
+		userConfig.FirstName = firstName
+		userConfig.LastName = lastName
+		userConfig.Organization = organization
+		userConfig.State = "idle"
+		workers := userConfig.MaxWorkers
+		userConfig.mu.Unlock()
+
+		b.sendMessage(chatID, fmt.Sprintf(
+			"<b>🎉 Setup Complete!</b>\n\n"+
+				"• First Name: <b>%s</b>\n"+
+				"• Last Name: <b>%s</b>\n"+
+				"• Organization: <b>%s</b>\n"+
+				"• Workers: <b>%d</b>\n\n"+
+				"Send /register when your files are uploaded",
+			htmlEscape(firstName), htmlEscape(lastName), htmlEscape(organization), workers,
+		))
+		return
+	}
+
 	userConfig.mu.Lock()
 	userConfig.State = "awaiting_firstname"
+	userConfig.PendingLines = nil
 	userConfig.mu.Unlock()
 
 	msg := "<b>⚙️ Setup Wizard</b>\n\n" +
-		"Let's configure your registration campaign.\n\n" +
+		"Let's configure your registration campaign. Send /cancel at any time to stop.\n\n" +
 		"<b>Step 1/3:</b> Please enter your <b>First Name</b>:"
 	b.sendMessage(chatID, msg)
 }
 
-// handleStateInput processes state-based user input
+// handleInlineList starts collecting pasted list lines until /done
+func (b *TelegramBot) handleInlineList(chatID int64, state, label string, userConfig *UserConfig) {
+	userConfig.mu.Lock()
+	userConfig.State = state
+	userConfig.PendingLines = nil
+	userConfig.mu.Unlock()
+
+	b.sendMessage(chatID, fmt.Sprintf(
+		"📝 Paste your %s, one per line (multiple messages are fine).\n\nSend /done when finished or /cancel to discard them.", label,
+	))
+}
+
+// finishInlineList validates the collected lines and, if any are valid,
+// saves them over the user's file. Caller must hold userConfig.mu.
+func (b *TelegramBot) finishInlineList(chatID int64, userConfig *UserConfig) {
+	targetFile := userConfig.EmailsFile
+	if userConfig.State == "awaiting_events" {
+		targetFile = userConfig.EventsFile
+	}
+	lines := userConfig.PendingLines
+	isEmails := userConfig.State == "awaiting_emails"
+	userConfig.State = "idle"
+	userConfig.PendingLines = nil
+
+	label := "event URLs"
+	if isEmails {
+		label = "emails"
+	}
+	if len(lines) == 0 {
+		b.sendMessage(chatID, fmt.Sprintf("📭 No %s pasted - kept <code>%s</code> as it was", label, htmlEscape(targetFile)))
+		return
+	}
+
+	// Check the list in a scratch file first so a bad paste can't replace a
+	// good list
+	scratch, err := os.CreateTemp(filepath.Dir(targetFile), ".inline-*"+filepath.Ext(targetFile))
+	if err != nil {
+		b.sendMessage(chatID, fmt.Sprintf("❌ Failed to save list: %s", htmlEscape(err)))
+		return
+	}
+	_, err = scratch.WriteString(strings.Join(lines, "\n") + "\n")
+	if closeErr := scratch.Close(); err == nil {
+		err = closeErr
+	}
+	defer os.Remove(scratch.Name())
+	if err != nil {
+		b.sendMessage(chatID, fmt.Sprintf("❌ Failed to save list: %s", htmlEscape(err)))
+		return
+	}
+
+	var count int
+	if isEmails {
+		var emails []string
+		emails, err = readEmails(scratch.Name(), b.logger)
+		count = len(emails)
+	} else {
+		var urls []string
+		urls, err = readEventURLs(scratch.Name(), b.logger)
+		count = len(urls)
+	}
+	if err != nil {
+		b.sendMessage(chatID, fmt.Sprintf("❌ Failed to read list: %s", htmlEscape(err)))
+		return
+	}
+	if count == 0 {
+		b.sendMessage(chatID, fmt.Sprintf("❌ No valid %s in the pasted lines - kept <code>%s</code> as it was", label, htmlEscape(targetFile)))
+		return
+	}
+	os.Chmod(scratch.Name(), 0644)
+	if err := os.Rename(scratch.Name(), targetFile); err != nil {
+		b.sendMessage(chatID, fmt.Sprintf("❌ Failed to save list: %s", htmlEscape(err)))
+		return
+	}
+
+	msg := fmt.Sprintf("✅ Saved <b>%d</b> valid %s to <code>%s</code>", count, label, htmlEscape(targetFile))
+	if skipped := len(lines) - count; skipped > 0 {
+		msg += fmt.Sprintf("\n⚠️ %d line(s) skipped as invalid", skipped)
+	}
+	b.sendMessage(chatID, msg)
+}
+
+// stateInputs describes what each input state is waiting for
+var stateInputs = map[string]string{
+	"awaiting_firstname": "your <b>First Name</b>",
+	"awaiting_lastname":  "your <b>Last Name</b>",
+	"awaiting_org":       "your <b>Organization Name</b>",
+	"awaiting_emails":    "email lines (send /done to save them)",
+	"awaiting_events":    "event URL lines (send /done to save them)",
+}
+
+// handleStateInput processes state-based user input. /cancel leaves the
+// state without keeping anything collected so far.
 func (b *TelegramBot) handleStateInput(chatID int64, text string, userConfig *UserConfig) {
 	userConfig.mu.Lock()
 	defer userConfig.mu.Unlock()
 
-	switch userConfig.State {
+	state := userConfig.State
+	if text == "/cancel" {
+		userConfig.State = "idle"
+		userConfig.PendingLines = nil
+		b.sendMessage(chatID, "🚫 Cancelled - nothing was saved")
+		return
+	}
+	// A command isn't an answer: say what's expected rather than saving it
+	if strings.HasPrefix(text, "/") && !(text == "/done" && (state == "awaiting_emails" || state == "awaiting_events")) {
+		b.sendMessage(chatID, fmt.Sprintf(
+			"⚠️ Still waiting for %s\n\nSend /cancel first to use <code>%s</code>",
+			stateInputs[state], htmlEscape(strings.Fields(text)[0]),
+		))
+		return
+	}
+
+	switch state {
+	case "awaiting_emails", "awaiting_events":
+		if text == "/done" {
+			b.finishInlineList(chatID, userConfig)
+			return
+		}
+		for _, line := range strings.Split(text, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				userConfig.PendingLines = append(userConfig.PendingLines, line)
+			}
+		}
+
 	case "awaiting_firstname":
-		userConfig.FirstName = text
+		userConfig.PendingLines = []string{text}
 		userConfig.State = "awaiting_lastname"
-		b.sendMessage(chatID, fmt.Sprintf("✅ First Name: <b>%s</b>\n\n<b>Step 2/3:</b> Please enter your <b>Last Name</b>:", text))
+		b.sendMessage(chatID, fmt.Sprintf("✅ First Name: <b>%s</b>\n\n<b>Step 2/3:</b> Please enter your <b>Last Name</b>:", htmlEscape(text)))
 
 	case "awaiting_lastname":
-		userConfig.LastName = text
+		userConfig.PendingLines = append(userConfig.PendingLines, text)
 		userConfig.State = "awaiting_org"
-		b.sendMessage(chatID, fmt.Sprintf("✅ Last Name: <b>%s</b>\n\n<b>Step 3/3:</b> Please enter your <b>Organization Name</b>:", text))
+		b.sendMessage(chatID, fmt.Sprintf("✅ Last Name: <b>%s</b>\n\n<b>Step 3/3:</b> Please enter your <b>Organization Name</b>:", htmlEscape(text)))
 
 	case "awaiting_org":
+		// Nothing is kept until the last step, so /cancel leaves the old details
+		userConfig.FirstName = userConfig.PendingLines[0]
+		userConfig.LastName = userConfig.PendingLines[1]
 		userConfig.Organization = text
 		userConfig.State = "idle"
+		userConfig.PendingLines = nil
 		msg := fmt.Sprintf(
 			"✅ Organization: <b>%s</b>\n\n"+
 				"<b>🎉 Setup Complete!</b>\n\n"+
@@ -392,7 +1048,7 @@ func (b *TelegramBot) handleStateInput(chatID int64, text string, userConfig *Us
 				"1. Upload files (emails.txt, events.txt)\n"+
 				"2. Optional: /workers &lt;number&gt; to change workers\n"+
 				"3. /register to start campaign",
-			text, userConfig.FirstName, userConfig.LastName, userConfig.Organization, userConfig.MaxWorkers,
+			htmlEscape(text), htmlEscape(userConfig.FirstName), htmlEscape(userConfig.LastName), htmlEscape(userConfig.Organization), userConfig.MaxWorkers,
 		)
 		b.sendMessage(chatID, msg)
 	}
@@ -415,20 +1071,103 @@ func (b *TelegramBot) sendWelcome(chatID int64) {
 	b.sendMessage(chatID, msg)
 }
 
+// handleTest runs one real registration in the background and reports the
+// detailed result, to validate a profile before a campaign
+func (b *TelegramBot) handleTest(chatID int64, text string, userConfig *UserConfig) {
+	eventURL, email, err := parseTestTarget(strings.TrimSpace(strings.TrimPrefix(text, "/test")))
+	if err != nil {
+		b.sendMessage(chatID, fmt.Sprintf("❌ %s\n\nUsage: <code>/test https://example.com/event/123 you@example.com</code>", htmlEscape(err)))
+		return
+	}
+
+	userConfig.mu.Lock()
+	contact := Contact{
+		Email:        email,
+		FirstName:    userConfig.FirstName,
+		LastName:     userConfig.LastName,
+		Organization: userConfig.Organization,
+	}
+	proxiesFile := userConfig.ProxiesFile
+	profileName := userConfig.Profile
+	retries := userConfig.Retries
+	userConfig.mu.Unlock()
+
+	if contact.FirstName == "" || contact.LastName == "" || contact.Organization == "" {
+		b.sendMessage(chatID, "❌ Please run /setup first to configure your details")
+		return
+	}
+	profile, ok := getFormTemplate(profileName)
+	if !ok {
+		b.sendMessage(chatID, fmt.Sprintf("❌ Unknown profile <code>%s</code>\n\nSend /profile to pick one", htmlEscape(profileName)))
+		return
+	}
+
+	// Track the test on the chat's campaign so /stop can cancel it
+	campaign := b.getCampaign(chatID)
+	ctx, cancel := context.WithCancel(context.Background())
+	campaign.mu.Lock()
+	if campaign.testCancel != nil {
+		campaign.mu.Unlock()
+		cancel()
+		b.sendMessage(chatID, "⚠️ A test registration is already running\n\nWait for its report or send /stop")
+		return
+	}
+	campaign.testCancel = cancel
+	campaign.mu.Unlock()
+
+	b.sendMessage(chatID, fmt.Sprintf("🧪 Testing registration of <code>%s</code>\non <code>%s</code>\n\nThis can take a minute...", htmlEscape(email), htmlEscape(eventURL)))
+	go func() {
+		defer func() {
+			campaign.mu.Lock()
+			campaign.testCancel = nil
+			campaign.mu.Unlock()
+			cancel()
+		}()
+
+		proxies, _ := readProxies(proxiesFile, b.logger)
+		result := runTestOne(ctx, eventURL, contact, profile, true, retries, proxies, b.logger)
+		if ctx.Err() != nil {
+			b.sendMessage(chatID, "⏹️ Test registration stopped")
+			return
+		}
+
+		icon := "✅"
+		if result.Status != "SUCCESS" {
+			icon = "❌"
+		}
+		report := htmlEscape(strings.Join(formatTestReport(result), "\n"))
+		b.sendMessage(chatID, fmt.Sprintf("%s <b>Test registration: %s</b>\n\n<pre>%s</pre>", icon, result.Status, report))
+	}()
+}
+
 // sendHelp sends help message
 func (b *TelegramBot) sendHelp(chatID int64) {
 	msg := "<b>📋 Available Commands</b>\n\n" +
 		"<b>Setup:</b>\n" +
 		"/setup - Configure first name, last name, organization\n" +
-		"/workers [number] - Set max concurrent workers\n" +
+		"/setup First|Last|Org - Configure them in one message\n" +
+		"/cancel - Leave the setup wizard or a pasted list\n" +
+		"/workers [number] - Set max concurrent workers, also for a running campaign\n" +
+		"/retries [number] - Set attempts per registration\n" +
+		"/emails, /events - Paste a list inline (end with /done)\n" +
+		"/profile [name] - List or select form profile\n" +
 		"/config - View current configuration\n\n" +
 		"<b>Campaign Control:</b>\n" +
-		"/register - Start registration campaign\n" +
-		"/stop - Stop running campaign\n" +
-		"/status - Check campaign status\n\n" +
+		"/register - Review and start registration campaign\n" +
+		"/dryrun - Check each event's form fields without submitting\n" +
+		"/test &lt;event URL&gt; &lt;email&gt; - Run one real registration and show how it went\n" +
+		"/pause - Pause after in-flight tasks finish\n" +
+		"/resume - Continue a paused campaign\n" +
+		"/stop - Stop running campaign or /test\n" +
+		"/status - Check campaign status\n" +
+		"/quiet [on|off] - Mute or unmute progress updates during campaigns\n\n" +
 		"<b>Information:</b>\n" +
-		"/results - View campaign results\n" +
-		"/stats - Show statistics\n\n" +
+		"/webhook [url|off] - POST results to a URL when done\n" +
+		"/results [failed] [eventID] [page] - Browse campaign results\n" +
+		"/results csv - All results as a spreadsheet\n" +
+		"/download - Download results, CSV and screenshots as zip\n" +
+		"/stats - Show statistics\n" +
+		"/proxystats - Show proxy reliability ranking\n\n" +
 		"<b>File Upload:</b>\n" +
 		"Send files named:\n" +
 		"• <code>emails.txt</code> - Email list\n" +
@@ -436,6 +1175,10 @@ func (b *TelegramBot) sendHelp(chatID int64) {
 		"• <code>proxies.txt</code> - Proxies (optional)\n\n" +
 		"<b>System:</b>\n" +
 		"/help - Show this help\n" +
+		"/version - Show build version\n" +
+		"/logs [count] - Admin: show recent log lines\n" +
+		"/verbose [on|off] - Admin: toggle debug logging\n" +
+		"/allow [chat ID], /deny &lt;chat ID&gt; - Admin: manage who may use the bot\n" +
 		"/start - Welcome message"
 	b.sendMessage(chatID, msg)
 }
@@ -443,38 +1186,159 @@ func (b *TelegramBot) sendHelp(chatID int64) {
 
 // sendStatus sends campaign status
 func (b *TelegramBot) sendStatus(chatID int64) {
-	b.campaign.mu.Lock()
-	defer b.campaign.mu.Unlock()
+	campaign := b.getCampaign(chatID)
+	campaign.mu.Lock()
+	defer campaign.mu.Unlock()
 
-	if !b.campaign.running {
+	if !campaign.running {
 		b.sendMessage(chatID, "⏸️ No campaign running\n\nSend /register to start")
 		return
 	}
 
-	elapsed := time.Since(b.campaign.startTime)
-	successful := 0
-	for _, r := range b.campaign.results {
-		if r.Status == "SUCCESS" {
-			successful++
-		}
+	elapsed := time.Since(campaign.startTime)
+	completed, successful, total := campaign.progress()
+
+	state := "🚀 <b>Campaign Running</b>"
+	if campaign.paused {
+		state = "⏸️ <b>Paused</b>\n\nSend /resume to continue"
 	}
 
 	msg := fmt.Sprintf(
-		"🚀 <b>Campaign Running</b>\n\n"+
+		"%s\n\n"+
 			"⏱️ Duration: %s\n"+
-			"📊 Completed: %d\n"+
+			"📊 Completed: %d/%d\n"+
+			"<code>%s</code>\n"+
 			"✅ Successful: %d\n"+
 			"❌ Failed: %d",
+		state,
 		elapsed.Round(time.Second),
-		len(b.campaign.results),
+		completed, total,
+		progressBar(completed, total),
 		successful,
-		len(b.campaign.results)-successful,
+		completed-successful,
 	)
+	if campaign.orchestrator != nil {
+		msg += fmt.Sprintf("\n👷 Workers: %d (change with /workers &lt;number&gt;)", campaign.orchestrator.Workers())
+	}
+	if campaign.orchestrator != nil && !campaign.paused && completed < total {
+		if eta, ok := campaign.orchestrator.ETA(); ok {
+			msg += fmt.Sprintf("\n⏳ ETA: %s remaining", formatETA(eta))
+		}
+	}
 	b.sendMessage(chatID, msg)
 }
 
-// handleRegister starts a registration campaign
+// progress returns live counts from the orchestrator, falling back to stored results.
+// Callers must hold cm.mu.
+func (cm *CampaignManager) progress() (completed, successful, total int) {
+	if cm.orchestrator != nil {
+		return cm.orchestrator.Progress()
+	}
+	for _, r := range cm.results {
+		if r.Status == "SUCCESS" {
+			successful++
+		}
+	}
+	return len(cm.results), successful, len(cm.results)
+}
+
+// abortStart marks a campaign that failed before its workers started as not running
+func (cm *CampaignManager) abortStart() {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.running = false
+}
+
+// handlePause holds the campaign before its next jobs, keeping the queue intact
+func (b *TelegramBot) handlePause(chatID int64) {
+	campaign := b.getCampaign(chatID)
+	campaign.mu.Lock()
+	defer campaign.mu.Unlock()
+
+	if !campaign.running || campaign.orchestrator == nil {
+		b.sendMessage(chatID, "⏸️ No campaign running")
+		return
+	}
+	if campaign.paused {
+		b.sendMessage(chatID, "⏸️ Campaign already paused\n\nSend /resume to continue")
+		return
+	}
+
+	campaign.orchestrator.Pause()
+	campaign.paused = true
+
+	completed, successful, total := campaign.progress()
+	b.sendMessage(chatID, fmt.Sprintf(
+		"⏸️ <b>Campaign Paused</b>\n\n"+
+			"📊 Completed: %d/%d\n"+
+			"✅ Successful: %d\n\n"+
+			"In-flight tasks will finish. Send /resume to continue",
+		completed, total, successful,
+	))
+}
+
+// handleResume continues a paused campaign
+func (b *TelegramBot) handleResume(chatID int64) {
+	campaign := b.getCampaign(chatID)
+	campaign.mu.Lock()
+	defer campaign.mu.Unlock()
+
+	if !campaign.running || !campaign.paused {
+		b.sendMessage(chatID, "▶️ No paused campaign")
+		return
+	}
+
+	campaign.orchestrator.Resume()
+	campaign.paused = false
+	b.sendMessage(chatID, "▶️ <b>Campaign Resumed</b>\n\nUse /status to check progress")
+}
+
+// handleRegister asks for confirmation before starting a registration campaign
 func (b *TelegramBot) handleRegister(chatID int64, userConfig *UserConfig) {
+	campaign := b.getCampaign(chatID)
+	userConfig.mu.Lock()
+	firstName := userConfig.FirstName
+	lastName := userConfig.LastName
+	organization := userConfig.Organization
+	emailsFile := userConfig.EmailsFile
+	eventsFile := userConfig.EventsFile
+	profileName := userConfig.Profile
+	maxWorkers := userConfig.MaxWorkers
+	fingerprint := userConfig.fingerprint()
+	userConfig.mu.Unlock()
+
+	if firstName == "" || lastName == "" || organization == "" {
+		b.sendMessage(chatID, "❌ Please run /setup first to configure your details")
+		return
+	}
+	campaign.mu.Lock()
+	running := campaign.running
+	campaign.mu.Unlock()
+	if running {
+		b.sendMessage(chatID, "⚠️ Campaign already running!\n\nSend /stop first")
+		return
+	}
+
+	msg := fmt.Sprintf(
+		"📝 <b>Start campaign?</b>\n\n"+
+			"👤 Name: <b>%s %s</b>\n"+
+			"🏢 Organization: <b>%s</b>\n"+
+			"📋 Profile: <b>%s</b>\n"+
+			"⚙️ Workers: <b>%d</b>\n\n"+
+			"📧 Emails: <code>%s</code>\n"+
+			"🎫 Events: <code>%s</code>",
+		htmlEscape(firstName), htmlEscape(lastName), htmlEscape(organization), htmlEscape(profileName), maxWorkers,
+		htmlEscape(emailsFile), htmlEscape(eventsFile),
+	)
+	b.sendMessageWithKeyboard(chatID, msg, [][]InlineKeyboardButton{
+		buttonRow("✅ Confirm", "register:confirm:"+fingerprint, "❌ Cancel", "register:cancel"),
+	})
+}
+
+// startCampaign loads the campaign files and starts a registration campaign.
+// A dry run only checks each event's form fields, using the first email.
+func (b *TelegramBot) startCampaign(chatID int64, userConfig *UserConfig, dryRun bool) {
+	campaign := b.getCampaign(chatID)
 	userConfig.mu.Lock()
 	firstName := userConfig.FirstName
 	lastName := userConfig.LastName
@@ -482,87 +1346,198 @@ func (b *TelegramBot) handleRegister(chatID int64, userConfig *UserConfig) {
 	emailsFile := userConfig.EmailsFile
 	eventsFile := userConfig.EventsFile
 	proxiesFile := userConfig.ProxiesFile
+	profileName := userConfig.Profile
+	webhookURL := userConfig.Webhook
 	maxWorkers := userConfig.MaxWorkers
+	retries := userConfig.Retries
 	userConfig.mu.Unlock()
 
+	profile, ok := getFormTemplate(profileName)
+	if !ok {
+		b.sendMessage(chatID, fmt.Sprintf("❌ Unknown profile <code>%s</code>\n\nSend /profile to pick one", htmlEscape(profileName)))
+		return
+	}
+
 	// Validate configuration
 	if firstName == "" || lastName == "" || organization == "" {
 		b.sendMessage(chatID, "❌ Please run /setup first to configure your details")
 		return
 	}
 
-	b.campaign.mu.Lock()
-	if b.campaign.running {
-		b.campaign.mu.Unlock()
+	campaign.mu.Lock()
+	if campaign.running {
+		campaign.mu.Unlock()
 		b.sendMessage(chatID, "⚠️ Campaign already running!\n\nSend /stop first")
 		return
 	}
-	b.campaign.running = true
-	b.campaign.startTime = time.Now()
-	b.campaign.results = []RegistrationResult{}
-	b.campaign.mu.Unlock()
+	campaign.running = true
+	campaign.dryRun = dryRun
+	campaign.startTime = time.Now()
+	campaign.results = []RegistrationResult{}
+	campaign.mu.Unlock()
 
-	emails, err := readEmails(emailsFile, b.logger)
+	contacts, err := readContacts(emailsFile, b.logger)
 	if err != nil {
-		b.campaign.running = false
-		b.sendMessage(chatID, fmt.Sprintf("❌ Failed to load emails from <code>%s</code>\n\nPlease upload emails.txt", emailsFile))
+		campaign.abortStart()
+		b.sendMessage(chatID, fmt.Sprintf("❌ Failed to load emails from <code>%s</code>\n\nPlease upload emails.txt", htmlEscape(emailsFile)))
 		return
 	}
 
 	eventURLs, err := readEventURLs(eventsFile, b.logger)
 	if err != nil {
-		b.campaign.running = false
-		b.sendMessage(chatID, fmt.Sprintf("❌ Failed to load events from <code>%s</code>\n\nPlease upload events.txt", eventsFile))
+		campaign.abortStart()
+		b.sendMessage(chatID, fmt.Sprintf("❌ Failed to load events from <code>%s</code>\n\nPlease upload events.txt", htmlEscape(eventsFile)))
 		return
 	}
+	if err := validateEventTemplates(eventURLs); err != nil {
+		campaign.abortStart()
+		b.sendMessage(chatID, fmt.Sprintf("❌ Invalid events list: %s", htmlEscape(err)))
+		return
+	}
+
+	if unreachable := unreachableEventURLs(checkEventURLs(eventURLs, 10*time.Second)); len(unreachable) > 0 {
+		var list strings.Builder
+		for _, check := range unreachable {
+			fmt.Fprintf(&list, "• <code>%s</code> (%s)\n", htmlEscape(check.URL), htmlEscape(check.Problem()))
+		}
+		if config.StrictURLs {
+			campaign.abortStart()
+			b.sendMessage(chatID, fmt.Sprintf("❌ <b>%d event URL(s) unreachable</b>\n\n%s\nFix events.txt and send /register again", len(unreachable), list.String()))
+			return
+		}
+		b.sendMessage(chatID, fmt.Sprintf("⚠️ <b>%d event URL(s) unreachable</b>\n\n%s\nStarting anyway - these events will likely fail", len(unreachable), list.String()))
+	}
 
 	proxies, _ := readProxies(proxiesFile, b.logger)
+	proxies, err = filterProxiesByCountry(proxies, config.Country)
+	if err != nil {
+		campaign.abortStart()
+		b.sendMessage(chatID, fmt.Sprintf("❌ Country filter %s: %s", htmlEscape(config.Country), htmlEscape(err)))
+		return
+	}
 
-	totalTasks := len(emails) * len(eventURLs)
+	title := "🚀 <b>Campaign Started!</b>"
+	if dryRun {
+		title = "🔎 <b>Dry Run Started</b> - nothing will be submitted"
+		if len(contacts) > 1 {
+			contacts = contacts[:1]
+		}
+	}
+	totalTasks := len(contacts) * len(eventURLs)
 
 	msg := fmt.Sprintf(
-		"🚀 <b>Campaign Started!</b>\n\n"+
+		"%s\n\n"+
 			"👤 Name: <b>%s %s</b>\n"+
 			"🏢 Organization: <b>%s</b>\n"+
+			"📋 Profile: <b>%s</b>\n"+
 			"⚙️ Workers: <b>%d</b>\n\n"+
 			"📧 Emails: %d\n"+
 			"🎫 Events: %d\n"+
 			"🔄 Total tasks: %d\n"+
 			"🌐 Proxies: %d\n\n"+
 			"Use /status to check progress",
-		firstName, lastName, organization, maxWorkers,
-		len(emails), len(eventURLs), totalTasks, len(proxies),
+		title, htmlEscape(firstName), htmlEscape(lastName), htmlEscape(organization), htmlEscape(profile.Name), maxWorkers,
+		len(contacts), len(eventURLs), totalTasks, len(proxies),
 	)
 	b.sendMessage(chatID, msg)
 
-	go b.runCampaign(chatID, firstName, lastName, organization, maxWorkers, emails, eventURLs, proxies)
+	ctx, cancel := context.WithCancel(context.Background())
+	campaign.mu.Lock()
+	campaign.cancel = cancel
+	campaign.mu.Unlock()
+
+	go b.runCampaign(ctx, chatID, firstName, lastName, organization, profile, maxWorkers, retries, webhookURL, contacts, eventURLs, proxies)
 }
 
 // runCampaign executes the registration campaign
-func (b *TelegramBot) runCampaign(chatID int64, firstName, lastName, organization string, maxWorkers int, emails, eventURLs []string, proxies []ProxyConfig) {
+func (b *TelegramBot) runCampaign(ctx context.Context, chatID int64, firstName, lastName, organization string, profile *FormTemplate, maxWorkers, retries int, webhookURL string, contacts []Contact, eventURLs []string, proxies []ProxyConfig) {
+	campaign := b.getCampaign(chatID)
 	orchestrator := NewRegistrationOrchestrator(
+		ctx,
 		firstName,
 		lastName,
 		organization,
+		profile,
 		true,
 		maxWorkers,
 		strconv.FormatInt(chatID, 10),
 		b.logger,
 	)
+	if b.newWorker != nil {
+		orchestrator.newWorker = b.newWorker
+	}
+	orchestrator.SetRetries(retries)
+	orchestrator.SetWebhook(webhookURL)
+	orchestrator.SetResultsPrefix(fmt.Sprintf("results_%d", chatID))
+	// Keep results current while the campaign runs so /results, /stats and
+	// /download show progress instead of waiting for the end
+	orchestrator.SetResultHandler(func(result RegistrationResult) {
+		campaign.mu.Lock()
+		campaign.results = append(campaign.results, result)
+		campaign.mu.Unlock()
+	})
+
+	campaign.mu.Lock()
+	campaign.orchestrator = orchestrator
+	campaign.paused = false
+	dryRun := campaign.dryRun
+	campaign.mu.Unlock()
+
+	if dryRun {
+		orchestrator.SetDryRun(true)
+		orchestrator.SetWebhook("")
+	}
 
-	results := orchestrator.Run(eventURLs, emails, proxies)
+	stopProgress := func() {}
+	if !dryRun && (config.ProgressInterval > 0 || config.ProgressStep > 0) {
+		stopProgress = b.watchProgress(chatID, orchestrator)
+	}
 
-	b.campaign.mu.Lock()
-	b.campaign.results = results
-	b.campaign.running = false
-	b.campaign.mu.Unlock()
+	campaignMetrics.Begin(orchestrator)
+	results := orchestrator.Run(eventURLs, contacts, proxies)
+	campaignMetrics.End(orchestrator)
+	stopProgress()
+	stopped := ctx.Err() != nil
+
+	campaign.mu.Lock()
+	startTime := campaign.startTime
+	campaign.results = results
+	campaign.running = false
+	campaign.paused = false
+	campaign.orchestrator = nil
+	if campaign.cancel != nil {
+		campaign.cancel()
+		campaign.cancel = nil
+	}
+	campaign.mu.Unlock()
+
+	if stopped {
+		totalTasks := len(contacts) * len(eventURLs)
+		b.sendMessage(chatID, fmt.Sprintf(
+			"⏹️ <b>Campaign Stopped</b>\n\n"+
+				"📊 Processed: %d/%d\n"+
+				"⏭️ Skipped: %d\n\n"+
+				"Send /results for partial results",
+			len(results), totalTasks, totalTasks-len(results),
+		))
+		return
+	}
+
+	if dryRun {
+		b.sendMessage(chatID, formatDryRunReport(results))
+		return
+	}
 
 	successful := 0
 	failed := 0
+	alreadyRegistered := 0
 	for _, r := range results {
-		if r.Status == "SUCCESS" {
+		switch r.Status {
+		case "SUCCESS":
 			successful++
-		} else {
+		case "ALREADY_REGISTERED":
+			alreadyRegistered++
+		default:
 			failed++
 		}
 	}
@@ -572,74 +1547,378 @@ func (b *TelegramBot) runCampaign(chatID int64, firstName, lastName, organizatio
 		successRate = float64(successful) / float64(len(results)) * 100
 	}
 
-	duration := time.Since(b.campaign.startTime)
+	duration := time.Since(startTime)
 
 	msg := fmt.Sprintf(
 		"✅ <b>Campaign Completed!</b>\n\n"+
 			"━━━━━━━━━━━━━━━━━━━━\n"+
 			"📊 Total: %d\n"+
 			"✅ Successful: %d\n"+
+			"↺ Already registered: %d\n"+
 			"❌ Failed: %d\n"+
 			"📈 Success Rate: %.1f%%\n"+
 			"⏱️ Duration: %s\n"+
 			"⚡ Rate: %.1f tasks/sec\n"+
 			"━━━━━━━━━━━━━━━━━━━━\n\n"+
 			"Send /results for details",
-		len(results), successful, failed, successRate,
+		len(results), successful, alreadyRegistered, failed, successRate,
 		duration.Round(time.Second),
 		float64(len(results))/duration.Seconds(),
 	)
 	b.sendMessage(chatID, msg)
 }
 
-// handleStop stops the running campaign
-func (b *TelegramBot) handleStop(chatID int64) {
-	b.campaign.mu.Lock()
-	defer b.campaign.mu.Unlock()
+// watchProgress pushes progress updates to chatID while orchestrator runs,
+// unless the chat is quiet or the campaign paused. The returned function
+// stops it, waiting for any update being sent.
+func (b *TelegramBot) watchProgress(chatID int64, orchestrator *RegistrationOrchestrator) func() {
+	userConfig := b.getUserConfig(chatID)
+	campaign := b.getCampaign(chatID)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+		notifier := newProgressNotifier(config.ProgressInterval, config.ProgressStep, time.Now())
+		ticker := time.NewTicker(progressPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				completed, successful, total := orchestrator.Progress()
+				if !notifier.due(completed, total, now) {
+					continue
+				}
+				userConfig.mu.Lock()
+				quiet := userConfig.Quiet
+				userConfig.mu.Unlock()
+				campaign.mu.Lock()
+				paused := campaign.paused
+				campaign.mu.Unlock()
+				if quiet || paused {
+					continue
+				}
+				eta, ok := orchestrator.ETA()
+				b.sendMessage(chatID, formatProgressUpdate(completed, successful, total, eta, ok))
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// handleQuiet mutes or unmutes progress updates for the chat
+func (b *TelegramBot) handleQuiet(chatID int64, text string, userConfig *UserConfig) {
+	parts := strings.Fields(text)
+	quiet := true
+	if len(parts) > 1 {
+		switch strings.ToLower(parts[1]) {
+		case "on":
+		case "off":
+			quiet = false
+		default:
+			b.sendMessage(chatID, "❌ Usage: /quiet [on|off]")
+			return
+		}
+	}
+
+	userConfig.mu.Lock()
+	userConfig.Quiet = quiet
+	userConfig.mu.Unlock()
+
+	if quiet {
+		b.sendMessage(chatID, "🔕 Progress updates muted\n\nSend /quiet off to turn them back on. /status still works.")
+		return
+	}
+	b.sendMessage(chatID, "🔔 Progress updates on")
+}
 
-	if !b.campaign.running {
+// formatDryRunReport lists each event's form check from a dry run
+func formatDryRunReport(results []RegistrationResult) string {
+	ready := 0
+	var lines strings.Builder
+	for _, r := range results {
+		icon := "❌"
+		if r.Status == "DRY_RUN" && r.Category == CategoryNone {
+			icon = "✅"
+			ready++
+		}
+		fmt.Fprintf(&lines, "%s <code>%s</code>\n%s\n\n", icon, htmlEscape(r.Event), htmlEscape(r.Message))
+	}
+	return fmt.Sprintf("🔎 <b>Dry Run Complete</b>\n\n%s<b>%d/%d events ready</b>", lines.String(), ready, len(results))
+}
+
+// handleStop stops the running campaign and any /test registration
+func (b *TelegramBot) handleStop(chatID int64) {
+	campaign := b.getCampaign(chatID)
+	campaign.mu.Lock()
+	defer campaign.mu.Unlock()
+
+	if campaign.testCancel != nil {
+		campaign.testCancel()
+		if !campaign.running {
+			b.sendMessage(chatID, "⏹️ Test registration stop requested")
+			return
+		}
+	}
+	if !campaign.running {
 		b.sendMessage(chatID, "⏸️ No campaign running")
 		return
 	}
 
-	b.campaign.running = false
-	b.sendMessage(chatID, "⏹️ Campaign stop requested\n\nWaiting for current tasks...")
+	if campaign.cancel != nil {
+		campaign.cancel()
+	}
+	b.sendMessage(chatID, "⏹️ Campaign stop requested\n\nCancelling in-flight tasks...")
 }
 
 // sendResults sends campaign results
-func (b *TelegramBot) sendResults(chatID int64) {
-	b.campaign.mu.Lock()
-	results := b.campaign.results
-	b.campaign.mu.Unlock()
+func (b *TelegramBot) sendResults(chatID int64, text string) {
+	campaign := b.getCampaign(chatID)
+	campaign.mu.Lock()
+	results := append([]RegistrationResult(nil), campaign.results...)
+	startTime := campaign.startTime
+	running := campaign.running
+	campaign.mu.Unlock()
 
 	if len(results) == 0 {
+		if running {
+			b.sendMessage(chatID, "⏳ No results yet\n\nThe campaign is starting - try again shortly")
+			return
+		}
 		b.sendMessage(chatID, "📭 No results yet\n\nRun /register first")
 		return
 	}
 
-	count := len(results)
-	if count > 10 {
-		count = 10
+	args := strings.Fields(text)[1:]
+
+	// /results csv attaches every result as a spreadsheet
+	if len(args) > 0 && strings.EqualFold(args[0], "csv") {
+		var data bytes.Buffer
+		if err := writeResultsCSV(&data, results); err != nil {
+			b.sendMessage(chatID, fmt.Sprintf("❌ Failed to build CSV: %s", htmlEscape(err)))
+			return
+		}
+		fileName := fmt.Sprintf("results_%s.csv", startTime.Format("20060102_150405"))
+		if err := b.sendDocument(chatID, fileName, data.Bytes(), fmt.Sprintf("📊 %d results", len(results))); err != nil {
+			b.sendMessage(chatID, fmt.Sprintf("❌ Failed to upload CSV: %s", htmlEscape(err)))
+		}
+		return
+	}
+
+	query := parseResultsQuery(args, results)
+	matched := query.filter(results)
+	if len(matched) == 0 {
+		b.sendMessage(chatID, fmt.Sprintf("📭 No results match <code>%s</code>\n\n%s", htmlEscape(query.command()), formatEventBreakdown(results)))
+		return
+	}
+
+	start, end, page, pages := pageBounds(len(matched), query.page)
+	query.page = page
+
+	successful := 0
+	for _, r := range matched {
+		if r.Status == "SUCCESS" {
+			successful++
+		}
 	}
 
-	msg := fmt.Sprintf("<b>📊 Last %d Results</b>\n\n", count)
-	for i := len(results) - count; i < len(results); i++ {
-		r := results[i]
+	title := "Results"
+	if query.failed {
+		title = "Failed Results"
+	}
+	if query.event != "" {
+		title += " for " + query.event
+	}
+	if running {
+		title += " so far"
+	}
+
+	msg := fmt.Sprintf(
+		"<b>📊 %s</b> (Page %d of %d)\n"+
+			"Total: %d | ✅ %d | ❌ %d\n\n",
+		htmlEscape(title), page, pages, len(matched), successful, len(matched)-successful,
+	)
+	if page == 1 && query.event == "" {
+		msg += formatEventBreakdown(matched) + "\n\n"
+	}
+	for _, r := range matched[start:end] {
 		status := "✅"
-		if r.Status == "FAILED" {
+		if r.Status != "SUCCESS" {
 			status = "❌"
 		}
 		msg += fmt.Sprintf(
 			"%s <code>%s</code>\n   Event: %s\n   %s\n\n",
-			status, truncateString(r.Email, 30), truncateString(r.Event, 40), r.Message,
+			status, htmlEscape(truncateString(r.Email, 30)), htmlEscape(truncateString(r.Event, 40)), htmlEscape(r.Message),
 		)
 	}
+	msg += "Send <code>/results csv</code> for all results as a spreadsheet"
 
-	b.sendMessage(chatID, msg)
+	var buttons []InlineKeyboardButton
+	if page > 1 {
+		prev := query
+		prev.page = page - 1
+		buttons = append(buttons, InlineKeyboardButton{Text: "◀️ Prev", CallbackData: prev.command()})
+	}
+	if page < pages {
+		next := query
+		next.page = page + 1
+		buttons = append(buttons, InlineKeyboardButton{Text: "Next ▶️", CallbackData: next.command()})
+	}
+	if len(buttons) == 0 {
+		b.sendMessage(chatID, msg)
+		return
+	}
+	b.sendMessageWithKeyboard(chatID, msg, [][]InlineKeyboardButton{buttons})
+}
+
+// resultsPageSize is how many results one /results page shows
+const resultsPageSize = 10
+
+// resultsQuery is a parsed /results request: [failed] [eventID] [page]
+type resultsQuery struct {
+	failed bool
+	event  string
+	page   int
+}
+
+// parseResultsQuery reads /results arguments. A number is a page unless it
+// matches one of the results' event IDs.
+func parseResultsQuery(args []string, results []RegistrationResult) resultsQuery {
+	events := make(map[string]bool)
+	for _, r := range results {
+		events[strings.ToLower(r.Event)] = true
+	}
+
+	query := resultsQuery{page: 1}
+	for _, arg := range args {
+		if strings.EqualFold(arg, "failed") {
+			query.failed = true
+			continue
+		}
+		if page, err := strconv.Atoi(arg); err == nil && !events[strings.ToLower(arg)] {
+			query.page = page
+			continue
+		}
+		query.event = eventKey(arg)
+	}
+	return query
+}
+
+// filter returns the results the query selects
+func (q resultsQuery) filter(results []RegistrationResult) []RegistrationResult {
+	var matched []RegistrationResult
+	for _, r := range results {
+		if q.failed && r.Status == "SUCCESS" {
+			continue
+		}
+		if q.event != "" && !strings.EqualFold(r.Event, q.event) {
+			continue
+		}
+		matched = append(matched, r)
+	}
+	return matched
+}
+
+// command rebuilds the /results command for this query, used as button callback data
+func (q resultsQuery) command() string {
+	parts := []string{"/results"}
+	if q.failed {
+		parts = append(parts, "failed")
+	}
+	if q.event != "" {
+		parts = append(parts, q.event)
+	}
+	if q.page > 1 {
+		parts = append(parts, strconv.Itoa(q.page))
+	}
+	return strings.Join(parts, " ")
+}
+
+// pageBounds clamps page to the available pages and returns its slice bounds
+func pageBounds(total, page int) (start, end, clamped, pages int) {
+	pages = (total + resultsPageSize - 1) / resultsPageSize
+	if pages == 0 {
+		pages = 1
+	}
+	clamped = min(max(page, 1), pages)
+	start = (clamped - 1) * resultsPageSize
+	end = min(start+resultsPageSize, total)
+	return start, end, clamped, pages
+}
+
+// formatEventBreakdown lists success/fail counts per event
+func formatEventBreakdown(results []RegistrationResult) string {
+	var sb strings.Builder
+	sb.WriteString("<b>Per event:</b>")
+	for _, e := range summarizeByEvent(results) {
+		fmt.Fprintf(&sb, "\n• <code>%s</code> ✅ %d ❌ %d (%.0f%%)", htmlEscape(e.Event), e.Successful, e.Failed, e.SuccessRate())
+	}
+	return sb.String()
+}
+
+// handleDownload uploads a zip with all campaign results and artifacts
+func (b *TelegramBot) handleDownload(chatID int64) {
+	campaign := b.getCampaign(chatID)
+	campaign.mu.Lock()
+	results := append([]RegistrationResult(nil), campaign.results...)
+	startTime := campaign.startTime
+	campaign.mu.Unlock()
+
+	if len(results) == 0 {
+		b.sendMessage(chatID, "📭 No results yet\n\nRun /register first")
+		return
+	}
+
+	archive, err := buildResultsArchive(results, startTime)
+	if err != nil {
+		b.sendMessage(chatID, fmt.Sprintf("❌ Failed to build archive: %s", htmlEscape(err)))
+		return
+	}
+
+	fileName := fmt.Sprintf("campaign_%s.zip", startTime.Format("20060102_150405"))
+	caption := fmt.Sprintf("📦 %d results", len(results))
+	if err := b.sendDocument(chatID, fileName, archive, caption); err != nil {
+		b.sendMessage(chatID, fmt.Sprintf("❌ Failed to upload archive: %s", htmlEscape(err)))
+	}
+}
+
+// sendDocument uploads an in-memory file to a chat
+func (b *TelegramBot) sendDocument(chatID int64, fileName string, data []byte, caption string) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("chat_id", strconv.FormatInt(chatID, 10))
+	writer.WriteField("caption", caption)
+
+	part, err := writer.CreateFormFile("document", fileName)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	status, respBody, err := postTelegram(fmt.Sprintf("%s/sendDocument", b.apiURL), writer.FormDataContentType(), body.Bytes(), strconv.FormatInt(chatID, 10), 60*time.Second, b.logger)
+	if err != nil {
+		return err
+	}
+	if status != 200 {
+		return fmt.Errorf("Telegram API error (HTTP %d): %s", status, string(respBody))
+	}
+	return nil
 }
 
 // sendStats sends statistics
 func (b *TelegramBot) sendStats(chatID int64) {
+	campaign := b.getCampaign(chatID)
 	userConfig := b.getUserConfig(chatID)
 
 	userConfig.mu.Lock()
@@ -653,10 +1932,11 @@ func (b *TelegramBot) sendStats(chatID int64) {
 	events, _ := readEventURLs(eventsFile, b.logger)
 	proxies, _ := readProxies(proxiesFile, b.logger)
 
-	b.campaign.mu.Lock()
-	running := b.campaign.running
-	resultsCount := len(b.campaign.results)
-	b.campaign.mu.Unlock()
+	campaign.mu.Lock()
+	running := campaign.running
+	resultsCount := len(campaign.results)
+	categories := countByCategory(campaign.results)
+	campaign.mu.Unlock()
 
 	status := "⏸️ Idle"
 	if running {
@@ -666,6 +1946,12 @@ func (b *TelegramBot) sendStats(chatID int64) {
 	estimatedBandwidth := maxWorkers * 2
 	estimatedRAM := float64(maxWorkers) * 0.15
 
+	browsersInUse, browserLimit := browserSlots.InUse()
+	browsers := fmt.Sprintf("%d", browsersInUse)
+	if browserLimit > 0 {
+		browsers = fmt.Sprintf("%d/%d", browsersInUse, browserLimit)
+	}
+
 	msg := fmt.Sprintf(
 		"<b>📊 System Statistics</b>\n\n"+
 			"<b>Status:</b> %s\n\n"+
@@ -676,14 +1962,56 @@ func (b *TelegramBot) sendStats(chatID int64) {
 			"👥 Max workers: %d\n\n"+
 			"<b>Estimated Resources:</b>\n"+
 			"📡 Bandwidth: ~%d Mbps\n"+
-			"💾 RAM: ~%.1f GB\n\n"+
+			"💾 RAM: ~%.1f GB\n"+
+			"🖥️ Browsers running (all chats): %s\n"+
+			"⏱️ Rate limit: %s\n\n"+
 			"<b>Campaign:</b>\n"+
 			"📝 Results cached: %d\n"+
 			"🆔 Your Chat ID: <code>%d</code>",
 		status, len(emails), len(events), len(proxies),
-		maxWorkers, estimatedBandwidth, estimatedRAM,
+		maxWorkers, estimatedBandwidth, estimatedRAM, browsers,
+		formatRate(effectiveRateLimit(len(proxies))),
 		resultsCount, chatID,
 	)
+
+	var breakdown strings.Builder
+	for _, category := range failureCategories {
+		if n := categories[category]; n > 0 {
+			fmt.Fprintf(&breakdown, "• %s: %d", category, n)
+			if advice := categoryAdvice[category]; advice != "" {
+				fmt.Fprintf(&breakdown, " (%s)", advice)
+			}
+			breakdown.WriteString("\n")
+		}
+	}
+	if breakdown.Len() > 0 {
+		msg += "\n\n<b>Failures by category:</b>\n" + strings.TrimSuffix(breakdown.String(), "\n")
+	}
+	b.sendMessage(chatID, msg)
+}
+
+// sendProxyStats sends the persisted proxy reliability ranking
+func (b *TelegramBot) sendProxyStats(chatID int64) {
+	if config.ProxyStatsFile == "" {
+		b.sendMessage(chatID, "⚠️ Proxy stats are disabled")
+		return
+	}
+
+	stats, err := OpenProxyStats(config.ProxyStatsFile)
+	if err != nil {
+		b.sendMessage(chatID, fmt.Sprintf("❌ %s", htmlEscape(err)))
+		return
+	}
+
+	ranked := stats.Ranked()
+	if len(ranked) == 0 {
+		b.sendMessage(chatID, "📭 No proxy stats yet\n\nStats are recorded after each campaign")
+		return
+	}
+
+	msg := fmt.Sprintf("<b>🌐 Proxy Ranking</b> (top %d of %d)\n\n<code>", min(len(ranked), 20), len(ranked))
+	msg += htmlEscape(strings.Join(formatProxyRanking(ranked, 20), "\n"))
+	msg += "</code>"
 	b.sendMessage(chatID, msg)
 }
 
@@ -702,50 +2030,167 @@ func (b *TelegramBot) handleConfig(chatID int64, userConfig *UserConfig) {
 			"• Emails: <code>%s</code>\n"+
 			"• Events: <code>%s</code>\n"+
 			"• Proxies: <code>%s</code>\n\n"+
+			"<b>Form Profile:</b> <code>%s</code>\n"+
+			"<b>Webhook:</b> <code>%s</code>\n\n"+
 			"<b>Performance:</b>\n"+
 			"• Max Workers: <b>%d</b>\n"+
 			"• Retry Attempts: %d\n\n"+
-			"Send /setup or /workers to change",
-		userConfig.FirstName, userConfig.LastName, userConfig.Organization,
-		userConfig.EmailsFile, userConfig.EventsFile, userConfig.ProxiesFile,
-		userConfig.MaxWorkers, config.RegistrationRetry,
+			"Send /setup, /workers, /retries or /profile to change",
+		htmlEscape(userConfig.FirstName), htmlEscape(userConfig.LastName), htmlEscape(userConfig.Organization),
+		htmlEscape(userConfig.EmailsFile), htmlEscape(userConfig.EventsFile), htmlEscape(userConfig.ProxiesFile),
+		htmlEscape(userConfig.Profile), htmlEscape(userConfig.Webhook), userConfig.MaxWorkers, userConfig.Retries,
 	)
 	b.sendMessage(chatID, msg)
 }
 
-// sendMessage sends a message to a chat
+// sendLogs sends the last lines of the bot's log, split across messages to
+// stay under Telegram's length limit. The log covers every chat's campaigns,
+// so only the admin may read it.
+func (b *TelegramBot) sendLogs(chatID int64, text string) {
+	if !b.access.IsAdmin(chatID) {
+		b.sendMessage(chatID, "❌ Only the bot's admin can read the logs")
+		return
+	}
+	count := defaultLogLines
+	if parts := strings.Fields(text); len(parts) > 1 {
+		n, err := strconv.Atoi(parts[1])
+		if len(parts) != 2 || err != nil || n < 1 || n > logHistorySize {
+			b.sendMessage(chatID, fmt.Sprintf("❌ Please provide a number of lines between 1 and %d", logHistorySize))
+			return
+		}
+		count = n
+	}
+
+	lines := recentLogs.Last(count)
+	if len(lines) == 0 {
+		b.sendMessage(chatID, "📭 No log lines yet")
+		return
+	}
+	for _, chunk := range chunkLogLines(lines, telegramMessageLimit) {
+		b.sendMessage(chatID, chunk)
+	}
+}
+
+// chunkLogLines escapes lines and packs them into <pre> messages of at most
+// limit characters; a line too long for one message is truncated
+func chunkLogLines(lines []string, limit int) []string {
+	const preOpen, preClose = "<pre>", "</pre>"
+	room := limit - len(preOpen) - len(preClose)
+
+	var chunks []string
+	var current strings.Builder
+	for _, line := range lines {
+		line = htmlEscape(line)
+		if len(line) > room-1 {
+			line = line[:room-4] + "..."
+			// Don't leave half an HTML entity at the cut
+			if amp := strings.LastIndex(line, "&"); amp >= 0 && !strings.Contains(line[amp:], ";") {
+				line = line[:amp] + "..."
+			}
+		}
+		if current.Len()+len(line)+1 > room {
+			chunks = append(chunks, preOpen+current.String()+preClose)
+			current.Reset()
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, preOpen+current.String()+preClose)
+	}
+	return chunks
+}
+
+// handleVerbose shows or toggles debug logging without a restart. It
+// affects the whole process, so only the admin may use it.
+func (b *TelegramBot) handleVerbose(chatID int64, text string) {
+	if !b.access.IsAdmin(chatID) {
+		b.sendMessage(chatID, "❌ Only the bot's admin can change debug logging")
+		return
+	}
+	parts := strings.Fields(text)
+	if len(parts) == 1 {
+		state := "off"
+		if b.logger.Verbose() {
+			state = "on"
+		}
+		b.sendMessage(chatID, fmt.Sprintf("🔎 Debug logging is <b>%s</b>\n\n<b>Usage:</b> /verbose on|off", state))
+		return
+	}
+
+	switch strings.ToLower(parts[1]) {
+	case "on":
+		b.logger.SetVerbose(true)
+		b.sendMessage(chatID, "✅ Debug logging on\n\nSee it with /logs")
+	case "off":
+		b.logger.SetVerbose(false)
+		b.sendMessage(chatID, "✅ Debug logging off")
+	default:
+		b.sendMessage(chatID, "❌ Usage: /verbose on|off")
+	}
+}
+
+// sendMessage sends a message to a chat, retrying when Telegram rate limits us
 func (b *TelegramBot) sendMessage(chatID int64, text string) {
+	b.sendMessageWithKeyboard(chatID, text, nil)
+}
+
+// sendMessageWithKeyboard sends a message with inline keyboard button rows.
+// Text over Telegram's length limit goes out in several messages, with the
+// keyboard on the last.
+func (b *TelegramBot) sendMessageWithKeyboard(chatID int64, text string, keyboard [][]InlineKeyboardButton) {
+	parts := splitMessage(text, telegramMessageLimit)
+	for i, part := range parts {
+		var buttons [][]InlineKeyboardButton
+		if i == len(parts)-1 {
+			buttons = keyboard
+		}
+		if !b.sendPart(chatID, part, buttons) {
+			return
+		}
+	}
+}
+
+// sendPart sends one message of at most telegramMessageLimit characters,
+// reporting whether Telegram accepted it
+func (b *TelegramBot) sendPart(chatID int64, text string, keyboard [][]InlineKeyboardButton) bool {
 	payload := map[string]interface{}{
 		"chat_id":    chatID,
 		"text":       text,
 		"parse_mode": "HTML",
 	}
+	if len(keyboard) > 0 {
+		payload["reply_markup"] = replyMarkup(keyboard)
+	}
 
 	jsonData, _ := json.Marshal(payload)
-	resp, err := http.Post(
-		fmt.Sprintf("%s/sendMessage", b.apiURL),
-		"application/json",
-		strings.NewReader(string(jsonData)),
-	)
 
+	// Shares the chat's pacing and 429 handling with alerts and screenshots
+	status, body, err := postTelegram(fmt.Sprintf("%s/sendMessage", b.apiURL), "application/json", jsonData, strconv.FormatInt(chatID, 10), 10*time.Second, b.logger)
 	if err != nil {
 		b.logger.Error("Failed to send message: %v", err)
-		return
+		return false
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		body, _ := io.ReadAll(resp.Body)
+	if status != 200 {
 		b.logger.Error("Telegram API error: %s", string(body))
+		return false
 	}
+	return true
 }
 
 // RunBotMode starts the application in bot mode
 func RunBotMode(logger *Logger) {
-	logger.Info(strings.Repeat("=", 70))
+	logger.Info("%s", strings.Repeat("=", 70))
 	logger.Info("TELEGRAM BOT MODE")
-	logger.Info(strings.Repeat("=", 70))
+	logger.Info("%s", strings.Repeat("=", 70))
 
 	bot := NewTelegramBot(config.TelegramToken, logger)
+	if bot.access.Open() {
+		logger.Warning("⚠️  Bot answers every chat - set %s or --allowed-chats to restrict it", allowedChatsEnv)
+	} else if config.AdminChat != 0 {
+		logger.Info("🔒 Bot restricted to admin chat %d and %d allowed chat(s)", config.AdminChat, len(bot.access.Chats()))
+	} else {
+		logger.Info("🔒 Bot restricted to %d allowed chat(s)", len(bot.access.Chats()))
+	}
 	bot.Start()
 }
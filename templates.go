@@ -0,0 +1,354 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// FormTemplate holds the selectors used to fill a registration form
+type FormTemplate struct {
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+	FirstName    string `json:"first_name,omitempty"`
+	LastName     string `json:"last_name,omitempty"`
+	Email        string `json:"email,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	Terms        string `json:"terms,omitempty"`
+	Submit       string `json:"submit,omitempty"`
+	SuccessTitle string `json:"success_title,omitempty"`
+	FormAnchor   string `json:"form_anchor,omitempty"` // Marker proving the form is on the page; defaults to FirstName
+
+	SuccessSelectors []string `json:"success_selectors,omitempty"` // Elements whose text confirms success
+	SuccessKeywords  []string `json:"success_keywords,omitempty"`  // Post-submit URL fragments meaning success
+	ErrorSelectors   []string `json:"error_selectors,omitempty"`   // Elements whose text is reported as the failure
+	ConsentSelectors []string `json:"consent_selectors,omitempty"` // Cookie banner accept buttons clicked before filling
+	RateLimit        float64  `json:"rate_limit,omitempty"`        // Attempts per second against each event host; overrides --event-rate-limit
+
+	CustomFields []CustomField `json:"custom_fields,omitempty"` // Extra fields filled after organization
+}
+
+// Custom field types
+const (
+	customFieldText     = "text"
+	customFieldSelect   = "select"
+	customFieldCheckbox = "checkbox"
+	customFieldRadio    = "radio"
+)
+
+// How a select field's value is matched against its options
+const (
+	selectByValue = "value"
+	selectByLabel = "label"
+)
+
+// CustomField is an extra form field such as a phone number, job title or
+// country dropdown. Its value is fixed or taken from a registrants column.
+type CustomField struct {
+	Name      string `json:"name,omitempty"` // Used in messages; defaults to the selector
+	Selector  string `json:"selector"`
+	Type      string `json:"type,omitempty"`       // text (default), select, checkbox or radio
+	Value     string `json:"value,omitempty"`      // Fixed value, or the fallback for value_from
+	ValueFrom string `json:"value_from,omitempty"` // Registrants column holding the value, e.g. "phone"
+	Match     string `json:"match,omitempty"`      // Select only: value or label; either by default
+}
+
+// label names the field in messages
+func (f CustomField) label() string {
+	if f.Name != "" {
+		return f.Name
+	}
+	return f.Selector
+}
+
+// fieldType returns the normalized type, text when unset
+func (f CustomField) fieldType() string {
+	if f.Type == "" {
+		return customFieldText
+	}
+	return strings.ToLower(f.Type)
+}
+
+// value returns the field's value for contact
+func (f CustomField) value(contact Contact) string {
+	if f.ValueFrom != "" {
+		if value := contact.Value(f.ValueFrom); value != "" {
+			return value
+		}
+	}
+	return f.Value
+}
+
+// checkedValue reports whether a checkbox value means ticked: anything but
+// false, no, off or 0, so an empty value ticks it
+func checkedValue(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "false", "no", "off", "0":
+		return false
+	}
+	return true
+}
+
+// Detection defaults shared by the bundled templates and inherited by loaded ones
+var (
+	defaultSuccessSelectors = []string{
+		".success-message",
+		"[data-testid='success-message']",
+		"text=success",
+		"text=registered",
+		"text=confirmation",
+	}
+	defaultSuccessKeywords = []string{"success", "confirmation", "thank", "registered", "complete"}
+	defaultErrorSelectors  = []string{
+		".error-message",
+		"[role='alert']",
+		".alert-danger",
+		"text=error",
+		"text=failed",
+	}
+	defaultConsentSelectors = []string{
+		"#onetrust-accept-btn-handler",
+		"#CybotCookiebotDialogBodyLevelButtonLevelOptinAllowAll",
+		"#wcpConsentBannerCtrl button:has-text('Accept')",
+		"button:has-text('Accept cookies')",
+		// A bare "Accept" or "I agree" button may be the form's own submit,
+		// so only look for those inside a cookie or consent banner
+		":is([id*=cookie], [class*=cookie], [id*=consent], [class*=consent]) button:has-text('Accept')",
+		":is([id*=cookie], [class*=cookie], [id*=consent], [class*=consent]) button:has-text('I agree')",
+	}
+)
+
+const defaultTemplateName = "default"
+
+// eventTemplateSeparator splits an event line into URL and template name ("url|template")
+const eventTemplateSeparator = "|"
+
+// formTemplates are the bundled templates plus any loaded from the selectors file
+var formTemplates = map[string]*FormTemplate{
+	defaultTemplateName: {
+		Name:         defaultTemplateName,
+		Description:  "Microsoft events registration form",
+		FirstName:    "#first_name",
+		LastName:     "#last_name",
+		Email:        "#email",
+		Organization: "#add3dffe-7bd0-4e39-872e-8398117afd53",
+		Terms:        "#ms-event-terms-and-conditions",
+		Submit:       "#submitRegistration",
+		SuccessTitle: "#modalSuccessTitle",
+
+		SuccessSelectors: defaultSuccessSelectors,
+		SuccessKeywords:  defaultSuccessKeywords,
+		ErrorSelectors:   defaultErrorSelectors,
+		ConsentSelectors: defaultConsentSelectors,
+	},
+	"generic": {
+		Name:         "generic",
+		Description:  "Generic form matched by input names",
+		FirstName:    "input[name*='first' i]",
+		LastName:     "input[name*='last' i]",
+		Email:        "input[type='email'], input[name*='email' i]",
+		Organization: "input[name*='org' i], input[name*='company' i]",
+		Terms:        "input[type='checkbox'][name*='terms' i]",
+		Submit:       "button[type='submit'], input[type='submit']",
+		SuccessTitle: ".success-message",
+
+		SuccessSelectors: defaultSuccessSelectors,
+		SuccessKeywords:  defaultSuccessKeywords,
+		ErrorSelectors:   defaultErrorSelectors,
+		ConsentSelectors: defaultConsentSelectors,
+	},
+}
+
+// formAnchor returns the selector checked after navigation, honoring --form-anchor
+func (t *FormTemplate) formAnchor() string {
+	if config.FormAnchor != "" {
+		return config.FormAnchor
+	}
+	if t.FormAnchor != "" {
+		return t.FormAnchor
+	}
+	return t.FirstName
+}
+
+// eventRateLimit returns the attempts per second allowed against each event
+// host using this template, 0 if unlimited
+func (t *FormTemplate) eventRateLimit() float64 {
+	if t != nil && t.RateLimit > 0 {
+		return t.RateLimit
+	}
+	return config.EventRateLimit
+}
+
+// withDefaults fills any empty selector from base
+func (t FormTemplate) withDefaults(base *FormTemplate) *FormTemplate {
+	fill := func(value *string, fallback string) {
+		if *value == "" {
+			*value = fallback
+		}
+	}
+	fill(&t.FirstName, base.FirstName)
+	fill(&t.LastName, base.LastName)
+	fill(&t.Email, base.Email)
+	fill(&t.Organization, base.Organization)
+	fill(&t.Terms, base.Terms)
+	fill(&t.Submit, base.Submit)
+	fill(&t.SuccessTitle, base.SuccessTitle)
+	if t.SuccessSelectors == nil {
+		t.SuccessSelectors = base.SuccessSelectors
+	}
+	if t.SuccessKeywords == nil {
+		t.SuccessKeywords = base.SuccessKeywords
+	}
+	if t.ErrorSelectors == nil {
+		t.ErrorSelectors = base.ErrorSelectors
+	}
+	if t.ConsentSelectors == nil {
+		t.ConsentSelectors = base.ConsentSelectors
+	}
+	return &t
+}
+
+// validate checks that the template can recognise a successful registration
+// and has no blank indicators (a blank keyword would match every URL)
+func (t *FormTemplate) validate() error {
+	lists := map[string][]string{
+		"success_selectors": t.SuccessSelectors,
+		"success_keywords":  t.SuccessKeywords,
+		"error_selectors":   t.ErrorSelectors,
+		"consent_selectors": t.ConsentSelectors,
+	}
+	for field, values := range lists {
+		for _, value := range values {
+			if strings.TrimSpace(value) == "" {
+				return fmt.Errorf("%s contains a blank entry", field)
+			}
+		}
+	}
+	if t.RateLimit < 0 {
+		return fmt.Errorf("rate_limit must not be negative")
+	}
+	for i, field := range t.CustomFields {
+		if strings.TrimSpace(field.Selector) == "" {
+			return fmt.Errorf("custom_fields[%d] has no selector", i)
+		}
+		switch field.fieldType() {
+		case customFieldText, customFieldSelect, customFieldRadio:
+			if field.Value == "" && field.ValueFrom == "" {
+				return fmt.Errorf("custom field %s needs a value or value_from", field.label())
+			}
+		case customFieldCheckbox:
+		default:
+			return fmt.Errorf("custom field %s has unknown type %q (expected text, select, checkbox or radio)", field.label(), field.Type)
+		}
+		if match := strings.ToLower(field.Match); match != "" && match != selectByValue && match != selectByLabel {
+			return fmt.Errorf("custom field %s has unknown match %q (expected value or label)", field.label(), field.Match)
+		}
+	}
+	if t.SuccessTitle == "" && len(t.SuccessSelectors) == 0 && len(t.SuccessKeywords) == 0 {
+		return errNoSuccessIndicator
+	}
+	return nil
+}
+
+// errNoSuccessIndicator rejects a template that can't recognise success
+var errNoSuccessIndicator = errors.New("needs at least one success indicator (success_title, success_selectors or success_keywords)")
+
+// urlIndicatesSuccess reports whether a post-submit URL contains a success keyword
+func (t *FormTemplate) urlIndicatesSuccess(url string) bool {
+	for _, keyword := range t.SuccessKeywords {
+		if contains(url, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadFormTemplates registers the templates in a selectors JSON file (an array
+// of templates). Selectors left out fall back to the default template. A
+// missing file is not an error.
+func loadFormTemplates(filename string, logger *Logger) error {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		logger.Debug("No selectors file at %s - using bundled templates", filename)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read selectors file: %v", err)
+	}
+
+	var templates []FormTemplate
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return fmt.Errorf("invalid selectors file %s: %v", filename, err)
+	}
+
+	for i, t := range templates {
+		name := strings.ToLower(strings.TrimSpace(t.Name))
+		if name == "" {
+			return fmt.Errorf("template #%d in %s has no name", i+1, filename)
+		}
+		t.Name = name
+		merged := t.withDefaults(formTemplates[defaultTemplateName])
+		if err := merged.validate(); err != nil {
+			return fmt.Errorf("template %q in %s: %v", name, filename, err)
+		}
+		// The inherited success_title is the default form's own modal, so a
+		// template that empties both lists must name its own title
+		if t.SuccessTitle == "" && len(merged.SuccessSelectors) == 0 && len(merged.SuccessKeywords) == 0 {
+			return fmt.Errorf("template %q in %s: %v", name, filename, errNoSuccessIndicator)
+		}
+		formTemplates[name] = merged
+		logger.Debug("Loaded form template: %s", name)
+	}
+
+	logger.Info("Loaded %d form templates from %s", len(templates), filename)
+	return nil
+}
+
+// getFormTemplate looks up a template by name (case-insensitive)
+func getFormTemplate(name string) (*FormTemplate, bool) {
+	template, ok := formTemplates[strings.ToLower(strings.TrimSpace(name))]
+	return template, ok
+}
+
+// formTemplateNames returns the template names in sorted order
+func formTemplateNames() []string {
+	names := make([]string, 0, len(formTemplates))
+	for name := range formTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// splitEventTemplate separates an event line into its URL and optional template name
+func splitEventTemplate(entry string) (string, string) {
+	eventURL, name, _ := strings.Cut(entry, eventTemplateSeparator)
+	return strings.TrimSpace(eventURL), strings.TrimSpace(name)
+}
+
+// eventTemplate resolves the URL and template for an event line, using
+// fallback when the line doesn't name a template
+func eventTemplate(entry string, fallback *FormTemplate) (string, *FormTemplate, error) {
+	eventURL, name := splitEventTemplate(entry)
+	if name == "" {
+		return eventURL, fallback, nil
+	}
+	template, ok := getFormTemplate(name)
+	if !ok {
+		return eventURL, fallback, fmt.Errorf("unknown template %q for %s (available: %s)", name, eventURL, strings.Join(formTemplateNames(), ", "))
+	}
+	return eventURL, template, nil
+}
+
+// validateEventTemplates checks that every template referenced by an event line exists
+func validateEventTemplates(entries []string) error {
+	for _, entry := range entries {
+		if _, _, err := eventTemplate(entry, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
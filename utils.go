@@ -2,15 +2,93 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"math"
+	"mime/multipart"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 )
 
+// EventURLCheck is the pre-flight result for one event URL
+type EventURLCheck struct {
+	URL    string
+	Status int    // HTTP status; 0 when the request failed
+	Body   string // Start of the page, for form checks
+	Err    error
+}
+
+// Reachable reports whether the URL answered without a 4xx/5xx status
+func (c EventURLCheck) Reachable() bool {
+	return c.Err == nil && c.Status < 400
+}
+
+// Problem describes why an unreachable URL failed
+func (c EventURLCheck) Problem() string {
+	if c.Err != nil {
+		return c.Err.Error()
+	}
+	return fmt.Sprintf("HTTP %d", c.Status)
+}
+
+// checkEventURLs fetches every event URL, a few at a time, and returns the
+// results in input order. Template suffixes are stripped. GET is used because
+// many single-page apps reject HEAD.
+func checkEventURLs(eventURLs []string, timeout time.Duration) []EventURLCheck {
+	client := &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	checks := make([]EventURLCheck, len(eventURLs))
+	sem := make(chan struct{}, 8)
+	var wg sync.WaitGroup
+	for i, entry := range eventURLs {
+		url, _ := splitEventTemplate(entry)
+		checks[i].URL = url
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(check *EventURLCheck) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			resp, err := client.Get(check.URL)
+			if err != nil {
+				check.Err = err
+				return
+			}
+			defer resp.Body.Close()
+			check.Status = resp.StatusCode
+
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 2<<20))
+			check.Body = string(body)
+		}(&checks[i])
+	}
+	wg.Wait()
+	return checks
+}
+
+// unreachableEventURLs returns the checks that failed
+func unreachableEventURLs(checks []EventURLCheck) []EventURLCheck {
+	var failed []EventURLCheck
+	for _, check := range checks {
+		if !check.Reachable() {
+			failed = append(failed, check)
+		}
+	}
+	return failed
+}
+
 // truncateString truncates a string to maxLen characters
 func truncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -28,6 +106,66 @@ func lastPathSegment(url string) string {
 	return url
 }
 
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// backoff returns the delay before retrying after attempt: RetryBaseDelay * 2^attempt
+// capped at RetryMaxDelay, with ±20% jitter so workers don't retry in lockstep
+func backoff(attempt int) time.Duration {
+	delay := config.RetryMaxDelay
+	if attempt < 31 {
+		delay = min(config.RetryBaseDelay*time.Duration(pow(2, attempt)), config.RetryMaxDelay)
+	}
+	jitter := (random.Float64()*0.4 - 0.2) * float64(delay)
+	return delay + time.Duration(jitter)
+}
+
+// DurationRange is an inclusive range of delays, written "min-max" (e.g. 300ms-1.5s)
+type DurationRange struct {
+	Min, Max time.Duration
+}
+
+// parseDurationRange parses "min-max", or a single duration for a fixed delay
+func parseDurationRange(s string) (DurationRange, error) {
+	minText, maxText, isRange := strings.Cut(strings.TrimSpace(s), "-")
+	if !isRange {
+		maxText = minText
+	}
+	minDelay, err := time.ParseDuration(strings.TrimSpace(minText))
+	if err != nil {
+		return DurationRange{}, fmt.Errorf("invalid delay range %q (expected e.g. 300ms-1.5s)", s)
+	}
+	maxDelay, err := time.ParseDuration(strings.TrimSpace(maxText))
+	if err != nil {
+		return DurationRange{}, fmt.Errorf("invalid delay range %q (expected e.g. 300ms-1.5s)", s)
+	}
+	if minDelay < 0 || maxDelay < minDelay {
+		return DurationRange{}, fmt.Errorf("invalid delay range %q (min must be between 0 and max)", s)
+	}
+	return DurationRange{Min: minDelay, Max: maxDelay}, nil
+}
+
+// Random returns a uniformly random delay within the range
+func (r DurationRange) Random() time.Duration {
+	if r.Max <= r.Min {
+		return r.Min
+	}
+	return r.Min + time.Duration(random.Int63n(int64(r.Max-r.Min)+1))
+}
+
+// String formats the range the way parseDurationRange reads it
+func (r DurationRange) String() string {
+	return fmt.Sprintf("%v-%v", r.Min, r.Max)
+}
+
 // pow calculates base^exp for integers
 func pow(base, exp int) int {
 	return int(math.Pow(float64(base), float64(exp)))
@@ -39,41 +177,159 @@ func sendTelegramAlert(message, chatID string, logger *Logger) bool {
 		logger.Debug("Telegram alert skipped: no chat ID provided")
 		return false
 	}
+	if config.TelegramAPI == "" {
+		logger.Debug("Telegram alert skipped: no bot token configured")
+		return false
+	}
+
+	// Telegram rejects messages over its length limit outright
+	for _, part := range splitMessage(message, telegramMessageLimit) {
+		payload := map[string]interface{}{
+			"chat_id":    chatID,
+			"text":       part,
+			"parse_mode": "HTML",
+		}
+
+		jsonData, err := json.Marshal(payload)
+		if err != nil {
+			logger.Error("Failed to marshal Telegram payload: %v", err)
+			return false
+		}
+
+		status, body, err := postTelegram(config.TelegramAPI, "application/json", jsonData, chatID, 10*time.Second, logger)
+		if err != nil {
+			logger.Error("Failed to send Telegram alert: %v", err)
+			return false
+		}
+		if status != 200 {
+			logger.Error("Telegram API error (HTTP %d): %s", status, string(body))
+			return false
+		}
+	}
+
+	logger.Debug("Telegram alert sent successfully to chat ID: %s", chatID)
+	return true
+}
+
+// telegramChatRates sends bot replies, alerts and photos to each chat one at
+// a time under Telegram's ~1 msg/sec per-chat limit, however many workers are
+// reporting
+var telegramChatRates = NewKeyedRateLimiter()
 
-	payload := map[string]interface{}{
-		"chat_id":    chatID,
-		"text":       message,
-		"parse_mode": "HTML",
+// postTelegram POSTs payload to a Telegram API method once chatID's turn
+// comes, waiting out HTTP 429 responses up to maxSendRetries times. It
+// returns the final status code and response body.
+func postTelegram(url, contentType string, payload []byte, chatID string, timeout time.Duration, logger *Logger) (int, []byte, error) {
+	client := &http.Client{Timeout: timeout}
+	for attempt := 0; ; attempt++ {
+		telegramChatRates.Wait(context.Background(), chatID, float64(time.Second)/float64(chatSendInterval))
+
+		resp, err := client.Post(url, contentType, bytes.NewReader(payload))
+		if err != nil {
+			return 0, nil, err
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxSendRetries {
+			retryAfter := parseRetryAfter(body)
+			logger.Warning("Telegram rate limit for chat %s - retrying in %v (%d/%d)", chatID, retryAfter, attempt+1, maxSendRetries)
+			time.Sleep(retryAfter)
+			continue
+		}
+		return resp.StatusCode, body, nil
 	}
+}
+
+// screenshotAlertInterval is the minimum gap between screenshot uploads to one chat
+const screenshotAlertInterval = 30 * time.Second
+
+var (
+	screenshotAlertMu   sync.Mutex
+	lastScreenshotAlert = make(map[string]time.Time)
+)
+
+// allowScreenshotAlert reports whether a screenshot may be sent to chatID now,
+// so a burst of unconfirmed results doesn't flood the chat
+func allowScreenshotAlert(chatID string) bool {
+	screenshotAlertMu.Lock()
+	defer screenshotAlertMu.Unlock()
 
-	jsonData, err := json.Marshal(payload)
+	if time.Since(lastScreenshotAlert[chatID]) < screenshotAlertInterval {
+		return false
+	}
+	lastScreenshotAlert[chatID] = time.Now()
+	return true
+}
+
+// sendTelegramPhoto uploads the image at path to chatID with an HTML caption
+func sendTelegramPhoto(path, caption, chatID string, logger *Logger) bool {
+	if config.TelegramAPI == "" {
+		logger.Debug("Telegram photo skipped: no bot token configured")
+		return false
+	}
+
+	data, err := os.ReadFile(path)
 	if err != nil {
-		logger.Error("Failed to marshal Telegram payload: %v", err)
+		logger.Error("Failed to read screenshot %s: %v", path, err)
 		return false
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Post(config.TelegramAPI, "application/json", bytes.NewBuffer(jsonData))
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("chat_id", chatID)
+	writer.WriteField("caption", caption)
+	writer.WriteField("parse_mode", "HTML")
+	part, err := writer.CreateFormFile("photo", filepath.Base(path))
 	if err != nil {
-		logger.Error("Failed to send Telegram alert: %v", err)
+		logger.Error("Failed to build Telegram photo upload: %v", err)
+		return false
+	}
+	part.Write(data)
+	if err := writer.Close(); err != nil {
+		logger.Error("Failed to build Telegram photo upload: %v", err)
 		return false
 	}
-	defer resp.Body.Close()
 
-	// Read response body for debugging
-	body, _ := io.ReadAll(resp.Body)
-	
-	if resp.StatusCode != 200 {
-		logger.Error("Telegram API error (HTTP %d): %s", resp.StatusCode, string(body))
+	// config.TelegramAPI points at sendMessage; photos go to the sibling method
+	photoURL := strings.TrimSuffix(config.TelegramAPI, "sendMessage") + "sendPhoto"
+	status, respBody, err := postTelegram(photoURL, writer.FormDataContentType(), body.Bytes(), chatID, 30*time.Second, logger)
+	if err != nil {
+		logger.Error("Failed to send Telegram photo: %v", err)
 		return false
 	}
 
-	logger.Debug("Telegram alert sent successfully to chat ID: %s", chatID)
+	if status != 200 {
+		logger.Error("Telegram API error (HTTP %d): %s", status, string(respBody))
+		return false
+	}
+
+	logger.Debug("Telegram photo sent to chat ID: %s", chatID)
 	return true
 }
 
+// parseRetryAfter extracts parameters.retry_after from a Telegram 429 response
+func parseRetryAfter(body []byte) time.Duration {
+	var result struct {
+		Parameters struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil || result.Parameters.RetryAfter <= 0 {
+		return 5 * time.Second
+	}
+	return time.Duration(result.Parameters.RetryAfter) * time.Second
+}
+
+// htmlEscape formats v for a parse_mode=HTML Telegram message, so names,
+// file names and error text containing <, > or & show up as written instead
+// of breaking the message
+func htmlEscape(v interface{}) string {
+	return html.EscapeString(fmt.Sprint(v))
+}
+
 // formatFailureAlert formats a failure message for Telegram
-func formatFailureAlert(email, eventURL string, attempt int, reason string) string {
+func formatFailureAlert(email, eventURL string, attempt, maxAttempts int, reason string) string {
 	event := truncateString(lastPathSegment(eventURL), 20)
 	return fmt.Sprintf(
 		"❌ <b>Registration Failed</b>\n"+
@@ -83,7 +339,7 @@ func formatFailureAlert(email, eventURL string, attempt int, reason string) stri
 		"🔄 Attempt: %d/%d\n"+
 		"❗️ Reason: %s\n"+
 		"⏰ Time: %s",
-		email, event, attempt, config.RegistrationRetry, reason,
+		htmlEscape(email), htmlEscape(event), attempt, maxAttempts, htmlEscape(reason),
 		time.Now().Format("2006-01-02 15:04:05"),
 	)
 }
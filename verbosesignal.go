@@ -0,0 +1,25 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// watchVerboseSignal flips debug logging each time the process gets SIGUSR1,
+// e.g. `kill -USR1 <pid>` once a running campaign needs a closer look
+func watchVerboseSignal(logger *Logger) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1)
+	go func() {
+		for range signals {
+			if logger.ToggleVerbose() {
+				logger.Info("🔎 Debug logging on (SIGUSR1)")
+			} else {
+				logger.Info("🔎 Debug logging off (SIGUSR1)")
+			}
+		}
+	}()
+}
@@ -0,0 +1,5 @@
+package main
+
+// watchVerboseSignal does nothing on Windows, which has no SIGUSR1; use the
+// bot's /verbose command instead
+func watchVerboseSignal(logger *Logger) {}
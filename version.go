@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Build information, set at build time with:
+//
+//	go build -ldflags "-X main.Version=1.0.0 -X main.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version   = "dev"
+	BuildTime = "unknown"
+)
+
+// buildRevision returns the VCS revision embedded by the Go toolchain, if any
+func buildRevision() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return truncateString(setting.Value, 12)
+		}
+	}
+	return ""
+}
+
+// versionString describes the running build
+func versionString() string {
+	version := Version
+	if rev := buildRevision(); rev != "" {
+		version += " (" + rev + ")"
+	}
+	return fmt.Sprintf("EventBlaster %s | Built: %s | Go: %s", version, BuildTime, runtime.Version())
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// webhookTimeout bounds each webhook delivery attempt
+const webhookTimeout = 10 * time.Second
+
+// WebhookPayload is the campaign summary POSTed to --webhook when a campaign finishes
+type WebhookPayload struct {
+	Total             int                  `json:"total"`
+	Successful        int                  `json:"successful"`
+	AlreadyRegistered int                  `json:"alreadyRegistered"`
+	Failed            int                  `json:"failed"`
+	Skipped           int                  `json:"skipped"`
+	SuccessRate       float64              `json:"successRate"`
+	DurationSeconds   float64              `json:"durationSeconds"`
+	Results           []RegistrationResult `json:"results"`
+}
+
+// newWebhookPayload summarizes results for delivery
+func newWebhookPayload(results []RegistrationResult, elapsed time.Duration) WebhookPayload {
+	payload := WebhookPayload{
+		Total:           len(results),
+		DurationSeconds: elapsed.Seconds(),
+		Results:         results,
+	}
+	for _, r := range results {
+		switch r.Status {
+		case "SUCCESS":
+			payload.Successful++
+		case "ALREADY_REGISTERED":
+			payload.AlreadyRegistered++
+		case "SKIPPED":
+			payload.Skipped++
+		default:
+			payload.Failed++
+		}
+	}
+	if ran := payload.Total - payload.Skipped; ran > 0 {
+		payload.SuccessRate = float64(payload.Successful) / float64(ran) * 100
+	}
+	return payload
+}
+
+// postWebhook delivers payload to url, retrying once on failure
+func postWebhook(url string, payload WebhookPayload, logger *Logger) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %v", err)
+	}
+
+	client := &http.Client{Timeout: webhookTimeout}
+	var lastErr error
+	for attempt := 1; attempt <= 2; attempt++ {
+		resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+		if err != nil {
+			lastErr = err
+			logger.Warning("Webhook attempt %d/2 failed: %v", attempt, err)
+			continue
+		}
+		resp.Body.Close()
+
+		logger.Info("Webhook %s responded with HTTP %d", url, resp.StatusCode)
+		if resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return fmt.Errorf("webhook delivery failed: %v", lastErr)
+}
@@ -1,279 +1,887 @@
 package main
 
 import (
+	"context"
+	"crypto/sha256"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/playwright-community/playwright-go"
 )
 
+// Worker executes registration tasks pulled from the orchestrator's queue
+type Worker interface {
+	ExecuteRegistration(ctx context.Context, eventURL string, template *FormTemplate, contact Contact) RegistrationResult
+	Close()
+}
+
+// maxProxySwitches bounds how many proxy errors per task are retried on a new
+// proxy without counting as an attempt
+const maxProxySwitches = 5
+
+// Allowed range for the number of attempts per task
+const (
+	minRegistrationRetries = 1
+	maxRegistrationRetries = 10
+)
+
 // RegistrationWorker handles individual registration tasks
 type RegistrationWorker struct {
 	workerID       int
-	proxies        []ProxyConfig
+	proxyPool      *ProxyPool
 	headless       bool
+	retries        int
 	telegramChatID string
+	sessionID      string
+	pw             *playwright.Playwright
+	browser        playwright.Browser
+	browserSlot    bool   // Holds a browserSlots slot while the browser runs
+	ramped         bool   // The --ramp-delay wait before the first launch is done
+	lastScreenshot string // Debug screenshot of the latest unconfirmed attempt
+	dryRun         bool   // Check the form fields without filling or submitting
 	logger         *Logger
 }
 
-func NewRegistrationWorker(workerID int, proxies []ProxyConfig, headless bool, telegramChatID string, logger *Logger) *RegistrationWorker {
+func NewRegistrationWorker(workerID int, proxyPool *ProxyPool, headless bool, retries int, telegramChatID string, logger *Logger) *RegistrationWorker {
 	return &RegistrationWorker{
 		workerID:       workerID,
-		proxies:        proxies,
+		proxyPool:      proxyPool,
 		headless:       headless,
+		retries:        retries,
 		telegramChatID: telegramChatID,
-		logger:         logger,
+		sessionID:      newSessionID(workerID),
+		logger:         logger.With("workerID", workerID),
+	}
+}
+
+// proxySessionPlaceholder is replaced in proxy credentials with a sticky session ID
+const proxySessionPlaceholder = "{session}"
+
+// newSessionID generates a session token that is unique per worker and call
+func newSessionID(workerID int) string {
+	return fmt.Sprintf("w%d%08x", workerID, random.Uint32())
+}
+
+// withSession returns a copy of the proxy with the {session} placeholder filled in
+func (p ProxyConfig) withSession(sessionID string) ProxyConfig {
+	p.Username = strings.ReplaceAll(p.Username, proxySessionPlaceholder, sessionID)
+	p.Password = strings.ReplaceAll(p.Password, proxySessionPlaceholder, sessionID)
+	return p
+}
+
+// proxySessionID returns the session ID for the next attempt based on the configured scope
+func (w *RegistrationWorker) proxySessionID() string {
+	if config.ProxySessionScope == "worker" {
+		return w.sessionID
+	}
+	return newSessionID(w.workerID)
+}
+
+// nextProxy draws the next proxy from the shared pool with its session filled in
+func (w *RegistrationWorker) nextProxy() *ProxyConfig {
+	proxy := w.proxyPool.Next()
+	if proxy == nil {
+		return nil
 	}
+	p := proxy.withSession(w.proxySessionID())
+	return &p
+}
+
+func (w *RegistrationWorker) ExecuteRegistration(ctx context.Context, eventURL string, template *FormTemplate, contact Contact) RegistrationResult {
+	registrationMetrics.TaskStarted()
+	start := time.Now()
+	result := w.executeRegistration(ctx, eventURL, template, contact)
+	registrationMetrics.TaskFinished(result.Status, time.Since(start))
+	return result
 }
 
-func (w *RegistrationWorker) ExecuteRegistration(eventURL, firstName, lastName, email, organization string) RegistrationResult {
-	var proxy *ProxyConfig
-	if len(w.proxies) > 0 {
-		proxy = &w.proxies[w.workerID%len(w.proxies)]
+// executeRegistration runs the attempts for one task
+func (w *RegistrationWorker) executeRegistration(ctx context.Context, eventURL string, template *FormTemplate, contact Contact) RegistrationResult {
+	email := contact.Email
+	logger := w.logger.With("email", email)
+	var history []AttemptRecord
+	proxyServer := ""
+	lastMessage := ""
+	lastCategory := CategoryNone
+
+	cancelled := func(attempt int) RegistrationResult {
+		logger.Warning("⏹️ %s - Cancelled at attempt %d", email, attempt)
+		return RegistrationResult{
+			Email:     email,
+			Contact:   contact,
+			Event:     eventKey(eventURL),
+			Status:    "CANCELLED",
+			Attempt:   attempt,
+			Message:   "Campaign stopped",
+			Proxy:     proxyServer,
+			Attempts:  history,
+			Timestamp: time.Now(),
+		}
 	}
 
-	for attempt := 1; attempt <= config.RegistrationRetry; attempt++ {
-		w.logger.Info("[%s] Attempt %d/%d", email, attempt, config.RegistrationRetry)
-		success, message := w.tryRegistration(eventURL, firstName, lastName, email, organization, proxy)
+	// With --pin-proxy every retry of this task reuses the first proxy drawn
+	var pinned *ProxyConfig
+
+	// Proxy errors switch to another proxy without using up an attempt
+	proxySwitches := 0
+
+	attempt := 1
+	for ; attempt <= w.retries; attempt++ {
+		if ctx.Err() != nil {
+			return cancelled(attempt - 1)
+		}
+
+		proxy := pinned
+		if proxy == nil {
+			proxy = w.nextProxy()
+			if config.PinProxy {
+				pinned = proxy
+			}
+		}
+		proxyServer = ""
+		if proxy != nil {
+			proxyServer = proxy.Server
+			if proxyRates.Wait(ctx, proxy.Server, config.ProxyRateLimit) != nil {
+				return cancelled(attempt - 1)
+			}
+		}
+		if eventRates.Wait(ctx, eventHost(eventURL), template.eventRateLimit()) != nil {
+			return cancelled(attempt - 1)
+		}
+
+		logger.Info("[%s] Attempt %d/%d", email, attempt, w.retries)
+		w.lastScreenshot = ""
+		success, message, category := w.attemptRegistration(ctx, eventURL, template, contact, proxy)
+		history = append(history, AttemptRecord{
+			Attempt:    attempt,
+			Proxy:      proxyServer,
+			Success:    success,
+			Category:   category,
+			Message:    message,
+			Screenshot: w.lastScreenshot,
+			Timestamp:  time.Now(),
+		})
+
+		if proxy != nil && category != CategoryProxyError {
+			w.proxyPool.ReportSuccess(proxy.Server)
+		}
 
 		if success {
-			w.logger.Info("✓ %s - Success", email)
+			logger.Info("✓ %s - Success", email)
 			return RegistrationResult{
 				Email:     email,
-				Event:     truncateString(lastPathSegment(eventURL), 20),
+				Contact:   contact,
+				Event:     eventKey(eventURL),
 				Status:    "SUCCESS",
 				Attempt:   attempt,
 				Message:   message,
+				Proxy:     proxyServer,
+				Attempts:  history,
 				Timestamp: time.Now(),
 			}
 		}
 
-		w.logger.Warning("✗ %s - Failed: %s", email, message)
+		if ctx.Err() != nil {
+			return cancelled(attempt)
+		}
 
-		if attempt < config.RegistrationRetry {
-			sleepDuration := time.Duration(pow(3, attempt)) * time.Second
-			w.logger.Debug("Retrying in %v...", sleepDuration)
-			time.Sleep(sleepDuration)
+		logger.Warning("✗ %s - Failed: %s", email, message)
+		lastMessage = message
+		lastCategory = category
+
+		if category == CategoryProxyError && proxy != nil {
+			if w.proxyPool.ReportFailure(proxy.Server) {
+				logger.Warning("🚫 Proxy %s removed from rotation after %d failures (%d left)", proxy.Server, config.ProxyMaxFailures, w.proxyPool.Healthy())
+			}
+			if proxySwitches < maxProxySwitches && w.proxyPool.Len() > 1 {
+				proxySwitches++
+				pinned = nil
+				attempt--
+				logger.Info("🔀 Proxy error - switching proxy (%d/%d)", proxySwitches, maxProxySwitches)
+				continue
+			}
+		}
+
+		// Nothing to retry when the email is already signed up or the dry run finished
+		if category == CategoryAlreadyRegistered || strings.HasPrefix(message, dryRunMessage) {
+			break
+		}
+
+		// In fail-fast mode a status from --fail-fast-on is reported as-is
+		// straight away, so the campaign aborts on the first one
+		if config.FailFast && isFailFastStatus(failureStatus(message)) {
+			break
+		}
+
+		if attempt < w.retries {
+			sleepDuration := backoff(attempt)
+			logger.Debug("Retrying in %v...", sleepDuration)
+			select {
+			case <-ctx.Done():
+			case <-time.After(sleepDuration):
+			}
 		} else {
 			// Send Telegram alert on final failure
-			if w.telegramChatID != "" {
-				alert := formatFailureAlert(email, eventURL, attempt, message)
-				sendTelegramAlert(alert, w.telegramChatID, w.logger)
+			if w.telegramChatID != "" && config.AlertMode != alertOff {
+				if config.AlertMode == alertBatched {
+					failureDigest.Add(w.telegramChatID, email, eventURL, category, message, logger)
+				} else {
+					alert := formatFailureAlert(email, eventURL, attempt, w.retries, message)
+					queueTelegramAlert(alert, w.telegramChatID, logger)
+				}
+				if message == unconfirmedMessage && w.lastScreenshot != "" {
+					w.sendUnconfirmedScreenshot(email, eventURL, logger)
+				}
 			}
 		}
 	}
 
+	failedMessage := "Max retries exceeded"
+	if attempt <= w.retries {
+		// Stopped early by --fail-fast
+		failedMessage = lastMessage
+	}
+	if status := failureStatus(lastMessage); status != "FAILED" {
+		return RegistrationResult{
+			Email:     email,
+			Contact:   contact,
+			Event:     eventKey(eventURL),
+			Status:    status,
+			Category:  lastCategory,
+			Attempt:   min(attempt, w.retries),
+			Message:   lastMessage,
+			Proxy:     proxyServer,
+			Attempts:  history,
+			Timestamp: time.Now(),
+		}
+	}
+
 	return RegistrationResult{
 		Email:     email,
-		Event:     truncateString(lastPathSegment(eventURL), 20),
+		Contact:   contact,
+		Event:     eventKey(eventURL),
 		Status:    "FAILED",
-		Attempt:   config.RegistrationRetry,
-		Message:   "Max retries exceeded",
+		Category:  lastCategory,
+		Attempt:   min(attempt, w.retries),
+		Message:   failedMessage,
+		Proxy:     proxyServer,
+		Attempts:  history,
 		Timestamp: time.Now(),
 	}
 }
 
-func (w *RegistrationWorker) tryRegistration(eventURL, firstName, lastName, email, organization string, proxy *ProxyConfig) (bool, string) {
-	// Install Playwright if needed (first run only)
-	err := playwright.Install()
-	if err != nil {
-		return false, fmt.Sprintf("Playwright install error: %v", err)
+// attemptRegistration performs one attempt, via direct API request in API mode or a browser otherwise
+func (w *RegistrationWorker) attemptRegistration(ctx context.Context, eventURL string, template *FormTemplate, contact Contact, proxy *ProxyConfig) (bool, string, FailureCategory) {
+	email := contact.Email
+	logger := w.logger.With("email", email)
+	if config.API != nil && !w.dryRun {
+		success, message, needsBrowser := registerViaAPI(ctx, config.API, proxy, eventURL, contact.FirstName, contact.LastName, email, contact.Organization, logger)
+		if !needsBrowser || !config.API.FallbackToBrowser {
+			if success {
+				return true, message, CategoryNone
+			}
+			return false, message, errorCategory(message)
+		}
+		logger.Warning("🌐 %s - API mode rejected (%s), falling back to browser", email, message)
+	}
+	return w.tryRegistration(ctx, eventURL, template, contact, proxy)
+}
+
+// BrowserLimiter bounds how many Chromium instances run at once across every
+// worker of every campaign. Slots go to whichever worker launches first and
+// are held until its browser closes: when the worker retires, finishes, or
+// waits out a pause or memory throttle.
+type BrowserLimiter struct {
+	slots chan struct{} // nil when unlimited
+	inUse atomic.Int32
+}
+
+// browserSlots is the limiter shared by all workers, sized by --max-browsers
+var browserSlots = NewBrowserLimiter(0)
+
+// NewBrowserLimiter allows max simultaneous browsers; max <= 0 means no limit
+func NewBrowserLimiter(max int) *BrowserLimiter {
+	l := &BrowserLimiter{}
+	if max > 0 {
+		l.slots = make(chan struct{}, max)
+	}
+	return l
+}
+
+// Acquire blocks until a browser may be launched or ctx is done
+func (l *BrowserLimiter) Acquire(ctx context.Context) error {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	l.inUse.Add(1)
+	return nil
+}
+
+// Release frees a slot taken by Acquire
+func (l *BrowserLimiter) Release() {
+	l.inUse.Add(-1)
+	if l.slots != nil {
+		<-l.slots
 	}
+}
+
+// InUse returns the number of running browsers and the limit (0 if unlimited)
+func (l *BrowserLimiter) InUse() (inUse, limit int) {
+	return int(l.inUse.Load()), cap(l.slots)
+}
 
-	// Start Playwright
+// launchRampDelay is how long worker workerID waits before its first browser launch:
+// workerID steps plus up to one step of jitter
+func launchRampDelay(workerID int, step time.Duration) time.Duration {
+	if step <= 0 {
+		return 0
+	}
+	return time.Duration(workerID)*step + time.Duration(random.Int63n(int64(step)))
+}
+
+// launchBrowser starts Playwright and the --browser engine on first use. The browser is
+// shared by every task this worker runs; each attempt gets a fresh context.
+// Launching waits for a browserSlots slot, which is held until Close.
+func (w *RegistrationWorker) launchBrowser(ctx context.Context) error {
+	if w.browser != nil {
+		if w.browser.IsConnected() {
+			return nil
+		}
+		w.logger.Warning("Browser disconnected - relaunching")
+		w.Close()
+	}
+
+	// Stagger first launches so workers don't all start Chromium at once
+	if !w.ramped {
+		w.ramped = true
+		if delay := launchRampDelay(w.workerID, config.RampDelay); delay > 0 {
+			w.logger.Debug("⏳ Ramp-up: waiting %v before launching the browser", delay.Round(time.Millisecond))
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("Waiting to launch the browser: %v", ctx.Err())
+			case <-time.After(delay):
+			}
+		}
+	}
+
+	// Repeated launch failures pause every worker's launches for a while
+	if err := launchBreaker.Wait(ctx); err != nil {
+		return err
+	}
+
+	if err := browserSlots.Acquire(ctx); err != nil {
+		return fmt.Errorf("Waiting for a browser slot: %v", err)
+	}
+	w.browserSlot = true
+
+	// Start Playwright; main already ran installPlaywright
 	pw, err := playwright.Run()
 	if err != nil {
-		return false, fmt.Sprintf("Could not start Playwright: %v", err)
+		w.Close()
+		return w.launchFailed(fmt.Errorf("Could not start Playwright: %v", err))
 	}
-	defer func() {
-		if err := pw.Stop(); err != nil {
-			w.logger.Error("Failed to stop Playwright: %v", err)
-		}
-	}()
 
-	// Launch browser
+	// Launch browser (proxies are applied per context); the flags are Chromium's own
 	launchOptions := playwright.BrowserTypeLaunchOptions{
 		Headless: playwright.Bool(w.headless),
-		Args: []string{
+	}
+	if config.Browser == browserChromium {
+		launchOptions.Args = []string{
 			"--disable-blink-features=AutomationControlled",
 			"--disable-dev-shm-usage",
 			"--no-sandbox",
 			"--disable-setuid-sandbox",
-			"--lang=en-US",                  // ← ADD THIS: Force English US locale
-        	"--accept-lang=en-US,en",        // ← ADD THIS: Accept language
-		},
+			"--lang=en-US",           // Force English US locale
+			"--accept-lang=en-US,en", // Accept language
+		}
 	}
 
-	if proxy != nil {
-		launchOptions.Proxy = &playwright.Proxy{
-			Server:   proxy.Server,
-			Username: playwright.String(proxy.Username),
-			Password: playwright.String(proxy.Password),
+	browser, err := browserType(pw, config.Browser).Launch(launchOptions)
+	if err != nil {
+		if err := pw.Stop(); err != nil {
+			w.logger.Error("Failed to stop Playwright: %v", err)
 		}
-		w.logger.Info("🌐 Using proxy: %s", proxy.Server)
-	} else {
-		w.logger.Warning("⚠️  No proxy configured - using direct connection")
+		w.Close()
+		return w.launchFailed(fmt.Errorf("Could not launch browser: %v", err))
 	}
 
-	browser, err := pw.Chromium.Launch(launchOptions)
-	if err != nil {
-		return false, fmt.Sprintf("Could not launch browser: %v", err)
+	launchBreaker.Success()
+	w.pw = pw
+	w.browser = browser
+	return nil
+}
+
+// playwrightInstall remembers the outcome of the one install check per process
+var playwrightInstall struct {
+	once sync.Once
+	err  error
+}
+
+// installBrowsers is playwright.Install, replaced in tests
+var installBrowsers = playwright.Install
+
+// prepareBrowser makes engine the --browser engine and, when install is set,
+// installs it. The engine must be set first so the install fetches it.
+func prepareBrowser(engine string, install bool) error {
+	if !validBrowserEngine(engine) {
+		return fmt.Errorf("Invalid --browser %q (expected %s)", engine, strings.Join(browserEngines, ", "))
 	}
-	defer func() {
-		if err := browser.Close(); err != nil {
+	config.Browser = engine
+	if !install {
+		return nil
+	}
+	if err := installPlaywright(); err != nil {
+		return fmt.Errorf("Playwright install error: %v", err)
+	}
+	return nil
+}
+
+// installPlaywright downloads the Playwright driver and the --browser engine
+// if they're missing. main calls it once before any worker starts.
+func installPlaywright() error {
+	playwrightInstall.once.Do(func() {
+		playwrightInstall.err = installBrowsers(&playwright.RunOptions{
+			Browsers: []string{config.Browser},
+			Verbose:  true,
+		})
+	})
+	return playwrightInstall.err
+}
+
+// Browser engines --browser accepts
+const (
+	browserChromium = "chromium"
+	browserFirefox  = "firefox"
+	browserWebKit   = "webkit"
+)
+
+var browserEngines = []string{browserChromium, browserFirefox, browserWebKit}
+
+// validBrowserEngine reports whether name is one of browserEngines
+func validBrowserEngine(name string) bool {
+	for _, engine := range browserEngines {
+		if name == engine {
+			return true
+		}
+	}
+	return false
+}
+
+// browserType returns the Playwright engine called name, Chromium by default
+func browserType(pw *playwright.Playwright, name string) playwright.BrowserType {
+	switch name {
+	case browserFirefox:
+		return pw.Firefox
+	case browserWebKit:
+		return pw.WebKit
+	default:
+		return pw.Chromium
+	}
+}
+
+// launchFailed records a failed launch with launchBreaker, logging a
+// diagnostic when it pauses launches, and returns err
+func (w *RegistrationWorker) launchFailed(err error) error {
+	if launchBreaker.Failure() {
+		w.logger.Error("🧯 Browser failed to launch %d times in a row (%v). %s", config.LaunchFailures, err, launchFailureHint)
+		if config.LaunchAbort {
+			w.logger.Error("🧯 Stopping browser launches (--abort-on-launch-failure)")
+		} else {
+			w.logger.Error("🧯 Pausing browser launches for %v", config.LaunchBackoff)
+		}
+	}
+	return err
+}
+
+// Close shuts down the worker's browser and Playwright driver if running
+func (w *RegistrationWorker) Close() {
+	if w.browser != nil {
+		if err := w.browser.Close(); err != nil {
 			w.logger.Error("Failed to close browser: %v", err)
 		}
-	}()
+		w.browser = nil
+	}
+	if w.pw != nil {
+		if err := w.pw.Stop(); err != nil {
+			w.logger.Error("Failed to stop Playwright: %v", err)
+		}
+		w.pw = nil
+	}
+	if w.browserSlot {
+		browserSlots.Release()
+		w.browserSlot = false
+	}
+}
 
-	// Create context
-	context, err := browser.NewContext(playwright.BrowserNewContextOptions{
+func (w *RegistrationWorker) tryRegistration(ctx context.Context, eventURL string, template *FormTemplate, contact Contact, proxy *ProxyConfig) (bool, string, FailureCategory) {
+	logger := w.logger.With("email", contact.Email)
+	if err := w.launchBrowser(ctx); err != nil {
+		// A stop while waiting to launch isn't a launch failure
+		if ctx.Err() != nil {
+			return false, err.Error(), CategoryNone
+		}
+		return false, err.Error(), CategoryBrowserLaunch
+	}
+
+	// Create context with a varied identity so contexts don't all look alike
+	contextOptions := playwright.BrowserNewContextOptions{
 		Locale:           playwright.String("en-US"),        // ← ADD THIS
 		TimezoneId:       playwright.String("America/New_York"), // ← ADD THIS
-		Viewport:  &playwright.Size{Width: 1248, Height: 836},
-		UserAgent: playwright.String("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"),
-	})
+	}
+	if err := applyBrowserIdentity(&contextOptions, w.pw.Devices, logger); err != nil {
+		return false, err.Error(), CategoryUnknown
+	}
+
+	if proxy != nil {
+		// Credentials on the context proxy let Playwright answer the proxy's
+		// 407 challenge; only set them when there are some to send
+		contextOptions.Proxy = &playwright.Proxy{Server: proxy.Server}
+		if proxy.Username != "" {
+			contextOptions.Proxy.Username = playwright.String(proxy.Username)
+			contextOptions.Proxy.Password = playwright.String(proxy.Password)
+		}
+		logger.Info("🌐 Using proxy: %s", proxy.Server)
+	} else {
+		logger.Warning("⚠️  No proxy configured - using direct connection")
+	}
+
+	// Cookies from an earlier success on this host skip consent banners and warmup redirects
+	if path, ok := savedStorageState(eventURL); ok {
+		contextOptions.StorageStatePath = playwright.String(path)
+		logger.Debug("🍪 Reusing cookies from %s", path)
+	}
+
+	browserContext, err := w.browser.NewContext(contextOptions)
+	if err != nil && contextOptions.StorageStatePath != nil {
+		logger.Warning("Discarding unreadable cookies %s: %v", *contextOptions.StorageStatePath, err)
+		os.Remove(*contextOptions.StorageStatePath)
+		contextOptions.StorageStatePath = nil
+		browserContext, err = w.browser.NewContext(contextOptions)
+	}
 	if err != nil {
-		return false, fmt.Sprintf("Could not create context: %v", err)
+		return false, fmt.Sprintf("Could not create context: %v", err), errorCategory(err.Error())
 	}
 	defer func() {
-		if err := context.Close(); err != nil {
-			w.logger.Error("Failed to close context: %v", err)
+		if err := browserContext.Close(); err != nil {
+			logger.Error("Failed to close context: %v", err)
 		}
 	}()
 
+	// Closing the context on cancellation aborts any in-flight navigation
+	stopCancelWatch := context.AfterFunc(ctx, func() {
+		browserContext.Close()
+	})
+	defer stopCancelWatch()
+
+	if len(config.BlockResources) > 0 {
+		if err := blockResources(browserContext, config.BlockResources); err != nil {
+			return false, fmt.Sprintf("Could not block resources: %v", err), errorCategory(err.Error())
+		}
+		logger.Debug("Blocking resources: %s", strings.Join(config.BlockResources, ", "))
+	}
+
+	if len(config.ExtraHeaders) > 0 {
+		if err := browserContext.SetExtraHTTPHeaders(config.ExtraHeaders); err != nil {
+			return false, fmt.Sprintf("Could not set extra headers: %v", err), errorCategory(err.Error())
+		}
+		logger.Debug("Extra headers set: %d", len(config.ExtraHeaders))
+	}
+
 	// Create page
-	page, err := context.NewPage()
+	page, err := browserContext.NewPage()
 	if err != nil {
-		return false, fmt.Sprintf("Could not create page: %v", err)
+		return false, fmt.Sprintf("Could not create page: %v", err), errorCategory(err.Error())
 	}
 	defer func() {
 		if err := page.Close(); err != nil {
-			w.logger.Error("Failed to close page: %v", err)
+			logger.Error("Failed to close page: %v", err)
 		}
 	}()
 
+	// Spread navigations so workers don't hit the target in lockstep
+	if config.StartJitter > 0 {
+		delay := time.Duration(random.Int63n(int64(config.StartJitter)))
+		logger.Debug("⏳ Start jitter: waiting %v before navigation", delay.Round(time.Millisecond))
+		select {
+		case <-ctx.Done():
+			return false, "Cancelled", CategoryUnknown
+		case <-time.After(delay):
+		}
+	}
+
 	// VERIFY PROXY IS WORKING - Check IP
 	if proxy != nil {
-		w.logger.Info("🔍 Verifying proxy connection...")
-		if _, err := page.Goto("https://api.ipify.org?format=json", playwright.PageGotoOptions{
-			Timeout: playwright.Float(10000),
-		}); err != nil {
-			w.logger.Warning("⚠️  Could not verify proxy IP: %v", err)
-		} else {
-			ipInfo, _ := page.Evaluate("() => document.body.innerText")
-			w.logger.Info("✅ Proxy IP check: %v", ipInfo)
+		if message, category := verifyProxy(page, logger); message != "" {
+			return false, message, category
 		}
 	}
 
+	loadedScreenshot := screenshotPath("page_loaded", w.workerID, contact.Email)
+	if w.dryRun {
+		return dryRunRegistration(ctx, page, template, eventURL, loadedScreenshot, logger)
+	}
+
 	// Perform registration
-	return performRegistration(page, eventURL, firstName, lastName, email, organization, w.logger)
+	debugScreenshot := screenshotPath("debug_screenshot", w.workerID, contact.Email)
+	success, message, category := performRegistration(ctx, page, template, eventURL, contact, loadedScreenshot, debugScreenshot, logger)
+	if success {
+		if err := saveStorageState(browserContext, eventURL); err != nil {
+			logger.Warning("Could not save cookies for %s: %v", eventHost(eventURL), err)
+		}
+	}
+	if message == unconfirmedMessage {
+		w.lastScreenshot = debugScreenshot
+	}
+	return success, message, category
+}
+
+// screenshotSeq numbers screenshots so that two taken in the same instant
+// still get different names
+var screenshotSeq atomic.Uint64
+
+// screenshotPath names a screenshot in --screenshot-dir after the worker, a
+// short hash of the email and a nanosecond timestamp plus sequence number, so
+// workers failing at the same moment never overwrite each other's files
+func screenshotPath(kind string, workerID int, email string) string {
+	hash := sha256.Sum256([]byte(strings.ToLower(email)))
+	name := fmt.Sprintf("%s_%d_%d_w%d_%x.png", kind, time.Now().UnixNano(), screenshotSeq.Add(1), workerID, hash[:4])
+	if config.ScreenshotDir == "" {
+		return name
+	}
+	if err := os.MkdirAll(config.ScreenshotDir, 0755); err != nil {
+		return name
+	}
+	return filepath.Join(config.ScreenshotDir, name)
+}
+
+// sendUnconfirmedScreenshot uploads the debug screenshot of an unconfirmed
+// registration to Telegram in the background and removes it once sent
+func (w *RegistrationWorker) sendUnconfirmedScreenshot(email, eventURL string, logger *Logger) {
+	path := w.lastScreenshot
+	w.lastScreenshot = ""
+
+	if !allowScreenshotAlert(w.telegramChatID) {
+		logger.Debug("Screenshot alert rate-limited - kept %s", path)
+		return
+	}
+
+	caption := fmt.Sprintf(
+		"🔍 <b>Unconfirmed Registration</b>\n"+
+			"📧 %s\n"+
+			"🎫 %s",
+		htmlEscape(email), htmlEscape(eventURL),
+	)
+	queueTelegramPhoto(path, caption, w.telegramChatID, logger)
 }
 
-func performRegistration(page playwright.Page, eventURL, firstName, lastName, email, organization string, logger *Logger) (bool, string) {
+// openRegistrationForm loads eventURL and makes sure the form is present and
+// any captcha is dealt with. It returns a failure message, or "" when the form
+// is ready to fill.
+func openRegistrationForm(ctx context.Context, page playwright.Page, template *FormTemplate, eventURL, loadedScreenshot string, logger *Logger) (string, FailureCategory) {
 	logger.Info("📄 Loading event URL...")
 
-	// Navigate to event page with LONGER timeout (60s instead of 15s)
-	if _, err := page.Goto(eventURL, playwright.PageGotoOptions{
-		Timeout:   playwright.Float(60000), // 60 seconds
-		WaitUntil: playwright.WaitUntilStateNetworkidle,
-	}); err != nil {
-		return false, fmt.Sprintf("Failed to load page: %v", err)
+	// Don't wait for the network to go idle (pages that poll never do); the
+	// form itself is waited for below
+	if err := gotoWithRetry(ctx, page, eventURL, playwright.PageGotoOptions{
+		Timeout:   milliseconds(config.PageLoadWait),
+		WaitUntil: playwright.WaitUntilStateDomcontentloaded,
+	}, logger); err != nil {
+		return navFailure(err)
 	}
 
 	logger.Info("✅ Page loaded successfully")
-	screenshotPath := fmt.Sprintf("page_loaded_%d.png", time.Now().Unix())
 	page.Screenshot(playwright.PageScreenshotOptions{
-		Path: playwright.String(screenshotPath),
+		Path: playwright.String(loadedScreenshot),
 		FullPage: playwright.Bool(true),
 	})
-	logger.Info("📸 Screenshot saved: %s", screenshotPath)
+	logger.Info("📸 Screenshot saved: %s", loadedScreenshot)
+
+	// Give JavaScript up to --element-wait to render the form, and make sure
+	// we landed on it and weren't redirected elsewhere
+	if anchor := template.formAnchor(); anchor != "" {
+		if err := page.Locator(anchor).First().WaitFor(playwright.LocatorWaitForOptions{
+			State:   playwright.WaitForSelectorStateAttached,
+			Timeout: milliseconds(config.ElementWait),
+		}); err != nil {
+			logger.Warning("Form marker %s not found on %s", anchor, page.URL())
+			return fmt.Sprintf("%s (landed on %s)", formNotPresentMessage, page.URL()), CategoryFormNotFound
+		}
+	}
+
+	// Proceed as soon as the form is usable; SPAs can attach it well before showing it
+	if err := page.Locator(template.FirstName).First().WaitFor(playwright.LocatorWaitForOptions{
+		State:   playwright.WaitForSelectorStateVisible,
+		Timeout: milliseconds(config.ElementWait),
+	}); err != nil {
+		logger.Warning("First name field %s never became visible on %s", template.FirstName, page.URL())
+		return fmt.Sprintf("%s: form never appeared (%s not visible after %v)", formNotPresentMessage, template.FirstName, config.ElementWait), CategoryFormNotFound
+	}
+
+	// Captcha widgets render with the form, so look for one only once it shows
+	if message := handleCaptcha(ctx, page, logger); message != "" {
+		return message, CategoryCaptcha
+	}
+	return "", CategoryNone
+}
+
+// dryRunMessage prefixes the report of a --dry-run task
+const dryRunMessage = "Dry run"
+
+// dryRunRegistration opens the form and checks that every configured selector
+// matches exactly one element. Nothing is filled or submitted.
+func dryRunRegistration(ctx context.Context, page playwright.Page, template *FormTemplate, eventURL, loadedScreenshot string, logger *Logger) (bool, string, FailureCategory) {
+	if message, category := openRegistrationForm(ctx, page, template, eventURL, loadedScreenshot, logger); message != "" {
+		return false, message, category
+	}
+
+	fields := []struct{ name, selector string }{
+		{"first name", template.FirstName},
+		{"last name", template.LastName},
+		{"email", template.Email},
+		{"organization", template.Organization},
+		{"terms", template.Terms},
+		{"submit", template.Submit},
+	}
+	for _, field := range template.CustomFields {
+		fields = append(fields, struct{ name, selector string }{field.label(), field.Selector})
+	}
+
+	var problems []string
+	for _, field := range fields {
+		count, err := page.Locator(field.selector).Count()
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("%s: %v", field.name, err))
+		case count == 0:
+			problems = append(problems, field.name+" missing")
+		case count > 1:
+			problems = append(problems, fmt.Sprintf("%s matches %d elements", field.name, count))
+		default:
+			logger.Info("🔎 %s found (%s)", field.name, field.selector)
+			continue
+		}
+		logger.Warning("🔎 %s (%s)", problems[len(problems)-1], field.selector)
+	}
+
+	if len(problems) > 0 {
+		return false, fmt.Sprintf("%s (%s): %s", dryRunMessage, template.Name, strings.Join(problems, ", ")), CategoryFormNotFound
+	}
+	return false, fmt.Sprintf("%s (%s): all %d fields found", dryRunMessage, template.Name, len(fields)), CategoryNone
+}
 
-	// Wait LONGER for JavaScript to render
-	page.WaitForTimeout(5000) // 5 seconds instead of 2
-	logger.Debug("📝 Filling form fields...")
+// dismissConsentBanner clicks the first visible cookie-consent accept button
+// so the banner can't intercept clicks meant for the form. Best effort: a
+// page without a banner, or a click that fails, is not an error.
+func dismissConsentBanner(page playwright.Page, selectors []string, logger *Logger) {
+	for _, selector := range selectors {
+		button := page.Locator(selector).First()
+		if visible, _ := button.IsVisible(); !visible {
+			continue
+		}
+		if err := button.Click(playwright.LocatorClickOptions{Timeout: milliseconds(probeTimeout)}); err != nil {
+			logger.Debug("Could not click consent button %s: %v", selector, err)
+			continue
+		}
+		logger.Debug("🍪 Dismissed cookie banner (%s)", selector)
+		return
+	}
+}
 
-	logger.Debug("📝 Filling form fields...")
+func performRegistration(ctx context.Context, page playwright.Page, template *FormTemplate, eventURL string, contact Contact, loadedScreenshot, debugScreenshot string, logger *Logger) (bool, string, FailureCategory) {
+	firstName, lastName, email, organization := contact.FirstName, contact.LastName, contact.Email, contact.Organization
+	if message, category := openRegistrationForm(ctx, page, template, eventURL, loadedScreenshot, logger); message != "" {
+		return false, message, category
+	}
+	dismissConsentBanner(page, template.ConsentSelectors, logger)
+
+	logger.Debug("📝 Filling form fields (template: %s)...", template.Name)
 
 	// Fill first name
-	if err := page.Locator("#first_name").Click(); err != nil {
-		return false, fmt.Sprintf("First name field not found: %v", err)
+	if err := page.Locator(template.FirstName).Click(); err != nil {
+		return false, fmt.Sprintf("First name field not found: %v", err), CategoryFormNotFound
 	}
-	if err := page.Locator("#first_name").Fill(firstName); err != nil {
-		return false, fmt.Sprintf("Failed to fill first name: %v", err)
+	if err := fillField(ctx, page.Locator(template.FirstName), firstName); err != nil {
+		return false, fmt.Sprintf("Failed to fill first name: %v", err), errorCategory(err.Error())
 	}
-	page.WaitForTimeout(500)
+	pauseAfterField(page)
 
 	// Fill last name
-	if err := page.Locator("#last_name").Click(); err != nil {
-		return false, fmt.Sprintf("Last name field not found: %v", err)
+	if err := page.Locator(template.LastName).Click(); err != nil {
+		return false, fmt.Sprintf("Last name field not found: %v", err), CategoryFormNotFound
 	}
-	if err := page.Locator("#last_name").Fill(lastName); err != nil {
-		return false, fmt.Sprintf("Failed to fill last name: %v", err)
+	if err := fillField(ctx, page.Locator(template.LastName), lastName); err != nil {
+		return false, fmt.Sprintf("Failed to fill last name: %v", err), errorCategory(err.Error())
 	}
-	page.WaitForTimeout(500)
+	pauseAfterField(page)
 
 	// Fill email
-	if err := page.Locator("#email").Click(); err != nil {
-		return false, fmt.Sprintf("Email field not found: %v", err)
+	if err := page.Locator(template.Email).Click(); err != nil {
+		return false, fmt.Sprintf("Email field not found: %v", err), CategoryFormNotFound
 	}
-	page.Locator("#email").Clear()
-	if err := page.Locator("#email").Fill(email); err != nil {
-		return false, fmt.Sprintf("Failed to fill email: %v", err)
+	page.Locator(template.Email).Clear()
+	if err := fillField(ctx, page.Locator(template.Email), email); err != nil {
+		return false, fmt.Sprintf("Failed to fill email: %v", err), errorCategory(err.Error())
 	}
-	page.WaitForTimeout(1000)
+	pauseAfterField(page)
 
 	// Fill organization
-	orgLocator := "#add3dffe-7bd0-4e39-872e-8398117afd53"
+	orgLocator := template.Organization
 	if err := page.Locator(orgLocator).Click(); err != nil {
-		return false, fmt.Sprintf("Organization field not found: %v", err)
+		return false, fmt.Sprintf("Organization field not found: %v", err), CategoryFormNotFound
+	}
+	if err := fillField(ctx, page.Locator(orgLocator), organization); err != nil {
+		return false, fmt.Sprintf("Failed to fill organization: %v", err), errorCategory(err.Error())
 	}
-	if err := page.Locator(orgLocator).Fill(organization); err != nil {
-		return false, fmt.Sprintf("Failed to fill organization: %v", err)
+	pauseAfterField(page)
+
+	// Fill the template's extra fields (phone, job title, country, ...)
+	if message, category := fillCustomFields(ctx, page, template.CustomFields, contact, logger); message != "" {
+		return false, message, category
 	}
-	page.WaitForTimeout(500)
 
 	// Accept terms
-	if err := page.Locator("#ms-event-terms-and-conditions").Click(); err != nil {
-		return false, fmt.Sprintf("Terms checkbox not found: %v", err)
+	if err := page.Locator(template.Terms).Click(); err != nil {
+		return false, fmt.Sprintf("Terms checkbox not found: %v", err), CategoryFormNotFound
 	}
-	page.WaitForTimeout(1000)
+	pauseAfterField(page)
 
 	// Submit
 	logger.Info("📤 Submitting registration...")
-	if err := page.Locator("#submitRegistration").Click(); err != nil {
-		return false, fmt.Sprintf("Submit button not found: %v", err)
+	if err := page.Locator(template.Submit).Click(); err != nil {
+		return false, fmt.Sprintf("Submit button not found: %v", err), CategoryFormNotFound
 	}
 
-	// Wait longer for server response
 	logger.Debug("⏳ Waiting for response...")
-	page.WaitForTimeout(5000)
+	page.WaitForTimeout(float64(config.SubmitWait.Milliseconds()))
 
 	// Check for success indicators (multiple strategies)
 	// Strategy 1: Check for success modal
-	successLocator := page.Locator("#modalSuccessTitle")
+	successLocator := page.Locator(template.SuccessTitle)
 	successText, err := successLocator.TextContent(playwright.LocatorTextContentOptions{
-		Timeout: playwright.Float(3000),
+		Timeout: milliseconds(config.ElementWait),
 	})
 	if err == nil && successText != "" {
 		logger.Info("✓ Registration successful: %s", successText)
-		return true, fmt.Sprintf("Success: %s", successText)
+		return true, successMessage(detectedByTitle, successText), CategoryNone
 	}
 
 	// Strategy 2: Check for any success-related elements
-	successVariants := []string{
-		".success-message",
-		"[data-testid='success-message']",
-		"text=success",
-		"text=registered",
-		"text=confirmation",
-	}
-	for _, selector := range successVariants {
+	for _, selector := range template.SuccessSelectors {
 		if elem := page.Locator(selector); elem != nil {
 			if text, err := elem.TextContent(playwright.LocatorTextContentOptions{
-				Timeout: playwright.Float(1000),
+				Timeout: milliseconds(probeTimeout),
 			}); err == nil && text != "" {
 				logger.Info("✓ Registration successful (found: %s)", selector)
-				return true, fmt.Sprintf("Success: %s", text)
+				return true, successMessage(detectedBySelector+" "+selector, text), CategoryNone
 			}
 		}
 	}
@@ -282,56 +890,416 @@ func performRegistration(page playwright.Page, eventURL, firstName, lastName, em
 	currentURL := page.URL()
 	if currentURL != eventURL {
 		logger.Debug("URL changed to: %s", currentURL)
-		if containsSuccessIndicator(currentURL) {
+		if template.urlIndicatesSuccess(currentURL) {
 			logger.Info("✓ Registration successful (URL redirect)")
-			return true, "Success: Redirected to success page"
+			return true, successMessage(detectedByURL, currentURL), CategoryNone
 		}
 	}
 
-	// Check for error messages
-	errorSelectors := []string{
-		".error-message",
-		"[role='alert']",
-		".alert-danger",
-		"text=error",
-		"text=failed",
-	}
-	for _, selector := range errorSelectors {
+	// Check for error messages. Only their text is searched for
+	// already-registered phrases: the rest of the page may hold links such as
+	// "Already registered? Log in" whatever the outcome.
+	for _, selector := range template.ErrorSelectors {
 		if elem := page.Locator(selector); elem != nil {
 			if text, err := elem.TextContent(playwright.LocatorTextContentOptions{
-				Timeout: playwright.Float(1000),
+				Timeout: milliseconds(probeTimeout),
 			}); err == nil && text != "" {
-				return false, fmt.Sprintf("Error: %s", text)
+				if keyword := matchAlreadyRegistered(text); keyword != "" {
+					logger.Info("↺ Already registered (found: %s)", keyword)
+					return false, fmt.Sprintf("%s (found: %s)", alreadyRegisteredMessage, keyword), CategoryAlreadyRegistered
+				}
+				return false, fmt.Sprintf("Error: %s", text), CategoryUnknown
 			}
 		}
 	}
 
+	// A challenge that only appears on submit leaves the status unconfirmed
+	if challenge := detectCaptcha(page); challenge != nil {
+		return false, fmt.Sprintf("%s: %s shown after submit", captchaBlockedMessage, challenge.Kind), CategoryCaptcha
+	}
+
 	// Take screenshot for debugging
-	screenshotPath = fmt.Sprintf("debug_screenshot_%d.png", time.Now().Unix())
 	page.Screenshot(playwright.PageScreenshotOptions{
-		Path: playwright.String(screenshotPath),
+		Path: playwright.String(debugScreenshot),
 	})
-	logger.Debug("Screenshot saved: %s", screenshotPath)
+	logger.Debug("Screenshot saved: %s", debugScreenshot)
+
+	return false, unconfirmedMessage, CategoryUnknown
+}
+
+// Success detection strategies, named in success messages
+const (
+	detectedByTitle    = "success title"
+	detectedBySelector = "success selector"
+	detectedByURL      = "URL redirect"
+	detectedByAPI      = "API"
+)
+
+// successMessage reports a success and the strategy that detected it
+func successMessage(strategy, detail string) string {
+	return fmt.Sprintf("Success (%s): %s", strategy, detail)
+}
+
+// detectionStrategy returns the strategy named in a success message, if any
+func detectionStrategy(message string) string {
+	rest, ok := strings.CutPrefix(message, "Success (")
+	if !ok {
+		return ""
+	}
+	strategy, _, ok := strings.Cut(rest, "): ")
+	if !ok {
+		return ""
+	}
+	return strategy
+}
+
+// unconfirmedMessage is returned when neither success nor error indicators were found
+const unconfirmedMessage = "Could not confirm registration status - check screenshot"
+
+// formNotPresentMessage prefixes failures where the page loaded without the expected form
+const formNotPresentMessage = "Registration form not present"
+
+// alreadyRegisteredMessage prefixes results where the email was already signed up
+const alreadyRegisteredMessage = "Already registered"
+
+// matchAlreadyRegistered returns the configured already-registered phrase found in text, if any
+func matchAlreadyRegistered(text string) string {
+	lower := strings.ToLower(text)
+	for _, keyword := range config.AlreadyRegistered {
+		if keyword != "" && strings.Contains(lower, keyword) {
+			return keyword
+		}
+	}
+	return ""
+}
+
+// failureStatus maps a final failure message to its result status
+func failureStatus(message string) string {
+	switch {
+	case message == unconfirmedMessage:
+		return "UNKNOWN_STATUS"
+	case strings.HasPrefix(message, formNotPresentMessage):
+		return "FORM_NOT_PRESENT"
+	case strings.HasPrefix(message, captchaBlockedMessage):
+		return "CAPTCHA_BLOCKED"
+	case strings.HasPrefix(message, alreadyRegisteredMessage):
+		return "ALREADY_REGISTERED"
+	case strings.HasPrefix(message, dryRunMessage):
+		return "DRY_RUN"
+	default:
+		return "FAILED"
+	}
+}
+
+// FailureCategory classifies why a registration attempt failed, so users can
+// tell proxy problems from selector problems
+type FailureCategory string
+
+const (
+	CategoryNone              FailureCategory = ""
+	CategoryTimeout           FailureCategory = "TIMEOUT"
+	CategoryFormNotFound      FailureCategory = "FORM_NOT_FOUND"
+	CategoryProxyError        FailureCategory = "PROXY_ERROR"
+	CategoryDNS               FailureCategory = "DNS_ERROR"
+	CategoryNetwork           FailureCategory = "NETWORK_ERROR"
+	CategoryCaptcha           FailureCategory = "CAPTCHA"
+	CategoryAlreadyRegistered FailureCategory = "ALREADY_REGISTERED"
+	CategoryBrowserLaunch     FailureCategory = "BROWSER_LAUNCH"
+	CategoryUnknown           FailureCategory = "UNKNOWN"
+)
+
+// failureCategories lists the categories in the order summaries report them
+var failureCategories = []FailureCategory{
+	CategoryTimeout,
+	CategoryFormNotFound,
+	CategoryProxyError,
+	CategoryDNS,
+	CategoryNetwork,
+	CategoryCaptcha,
+	CategoryAlreadyRegistered,
+	CategoryBrowserLaunch,
+	CategoryUnknown,
+}
+
+// proxyErrorMarkers identify network errors caused by the proxy rather than the event site
+var proxyErrorMarkers = []string{
+	"ERR_PROXY",
+	"ERR_TUNNEL_CONNECTION_FAILED",
+	"ERR_SOCKS_CONNECTION_FAILED",
+	"ERR_CONNECTION_RESET",
+	"ERR_CONNECTION_CLOSED",
+	"ERR_EMPTY_RESPONSE",
+	"proxyconnect",
+	"407 Proxy Authentication Required",
+}
+
+// errorCategory classifies a Playwright or HTTP error message
+func errorCategory(message string) FailureCategory {
+	if strings.HasPrefix(message, alreadyRegisteredMessage) {
+		return CategoryAlreadyRegistered
+	}
+	if navErr := matchNavError(message); navErr != nil {
+		return navErr.category
+	}
+	lower := strings.ToLower(message)
+	if strings.Contains(lower, "timeout") || strings.Contains(lower, "deadline exceeded") || strings.Contains(message, "ERR_TIMED_OUT") {
+		return CategoryTimeout
+	}
+	for _, marker := range proxyErrorMarkers {
+		if strings.Contains(message, marker) {
+			return CategoryProxyError
+		}
+	}
+	return CategoryUnknown
+}
+
+// navError is a Chromium network error code with its category and a plain
+// explanation
+type navError struct {
+	code     string
+	category FailureCategory
+	hint     string
+}
+
+// navErrors tell a dead proxy from a DNS failure from a slow site
+var navErrors = []navError{
+	{"ERR_NAME_NOT_RESOLVED", CategoryDNS, "event domain does not resolve (DNS)"},
+	{"ERR_NAME_RESOLUTION_FAILED", CategoryDNS, "DNS lookup failed"},
+	{"ERR_PROXY_CONNECTION_FAILED", CategoryProxyError, "could not connect to the proxy"},
+	{"ERR_TUNNEL_CONNECTION_FAILED", CategoryProxyError, "proxy could not reach the site"},
+	{"ERR_SOCKS_CONNECTION_FAILED", CategoryProxyError, "could not connect through the SOCKS proxy"},
+	{"ERR_PROXY_AUTH_UNSUPPORTED", CategoryProxyError, "proxy asked for an unsupported kind of login"},
+	{"ERR_INVALID_AUTH_CREDENTIALS", CategoryProxyError, "proxy rejected the username/password"},
+	{"ERR_PROXY_AUTH_REQUESTED", CategoryProxyError, "proxy wants a username/password but none was given"},
+	{"ERR_TIMED_OUT", CategoryTimeout, "site took too long to respond"},
+	{"ERR_CONNECTION_TIMED_OUT", CategoryTimeout, "connection to the site timed out"},
+	{"ERR_CONNECTION_REFUSED", CategoryNetwork, "site refused the connection"},
+	{"ERR_ADDRESS_UNREACHABLE", CategoryNetwork, "site address is unreachable"},
+	{"ERR_INTERNET_DISCONNECTED", CategoryNetwork, "no internet connection"},
+}
+
+// matchNavError returns the known network error named in message, if any
+func matchNavError(message string) *navError {
+	for i := range navErrors {
+		if strings.Contains(message, navErrors[i].code) {
+			return &navErrors[i]
+		}
+	}
+	return nil
+}
+
+// navFailure describes a failed page load in plain words, keeping the
+// Chromium error code for reference
+func navFailure(err error) (string, FailureCategory) {
+	if navErr := matchNavError(err.Error()); navErr != nil {
+		return fmt.Sprintf("Failed to load page: %s (%s)", navErr.hint, navErr.code), navErr.category
+	}
+	return fmt.Sprintf("Failed to load page: %v", err), errorCategory(err.Error())
+}
+
+// categoryAdvice tells operators what to fix for the network failure categories
+var categoryAdvice = map[FailureCategory]string{
+	CategoryProxyError: "check or replace proxies",
+	CategoryDNS:        "check the event domains",
+	CategoryNetwork:    "sites are refusing connections",
+	CategoryTimeout:    "slow sites or proxies - raise --page-load-wait",
+}
 
-	return false, "Could not confirm registration status - check screenshot"
+// countByCategory tallies failed results per failure category
+func countByCategory(results []RegistrationResult) map[FailureCategory]int {
+	counts := make(map[FailureCategory]int)
+	for _, r := range results {
+		if r.Status == "SUCCESS" || r.Status == "CANCELLED" || r.Status == "SKIPPED" {
+			continue
+		}
+		category := r.Category
+		if category == CategoryNone {
+			category = CategoryUnknown
+		}
+		counts[category]++
+	}
+	return counts
 }
 
-// containsSuccessIndicator checks if URL contains success indicators
-func containsSuccessIndicator(url string) bool {
-	successKeywords := []string{"success", "confirmation", "thank", "registered", "complete"}
-	urlLower := fmt.Sprintf("%v", url)
-	for _, keyword := range successKeywords {
-		if contains(urlLower, keyword) {
+// transientNavErrors are network errors worth retrying on the same page
+var transientNavErrors = []string{
+	"ERR_CONNECTION_RESET",
+	"ERR_CONNECTION_CLOSED",
+	"ERR_CONNECTION_ABORTED",
+	"ERR_EMPTY_RESPONSE",
+	"ERR_NETWORK_CHANGED",
+	"ERR_SOCKET_NOT_CONNECTED",
+	"ERR_HTTP2_PROTOCOL_ERROR",
+}
+
+// isTransientNavError reports whether a navigation error is a transient network failure
+func isTransientNavError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	for _, code := range transientNavErrors {
+		if strings.Contains(msg, code) {
 			return true
 		}
 	}
 	return false
 }
 
+// gotoWithRetry navigates to url, quickly retrying transient network errors
+// without consuming a full task-level attempt. Once ctx is cancelled it stops
+// retrying and returns ctx.Err().
+func gotoWithRetry(ctx context.Context, page playwright.Page, url string, options playwright.PageGotoOptions, logger *Logger) error {
+	var err error
+	for try := 0; try <= config.NavRetries; try++ {
+		if try > 0 {
+			logger.Warning("🔁 Transient navigation error, quick retry %d/%d: %v", try, config.NavRetries, err)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(config.NavRetryDelay):
+			}
+		}
+		if _, err = page.Goto(url, options); err == nil || !isTransientNavError(err) {
+			return err
+		}
+	}
+	return err
+}
+
+// fillField sets a form field's value. With --humanize it is typed one key at
+// a time with random pauses instead of filled instantly, stopping early if
+// ctx is cancelled.
+func fillField(ctx context.Context, locator playwright.Locator, value string) error {
+	if !config.Humanize {
+		return locator.Fill(value)
+	}
+	if err := locator.Clear(); err != nil {
+		return err
+	}
+	for _, r := range value {
+		if err := locator.PressSequentially(string(r)); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(config.KeystrokeDelay.Random()):
+		}
+	}
+	return nil
+}
+
+// fillCustomFields fills each custom field with its value for contact,
+// returning a failure message if one can't be filled. Text and select
+// fields without a value are left alone.
+func fillCustomFields(ctx context.Context, page playwright.Page, fields []CustomField, contact Contact, logger *Logger) (string, FailureCategory) {
+	for _, field := range fields {
+		value := field.value(contact)
+		locator := page.Locator(field.Selector)
+
+		var err error
+		switch field.fieldType() {
+		case customFieldCheckbox:
+			err = locator.SetChecked(checkedValue(value))
+		case customFieldSelect, customFieldRadio:
+			if value == "" {
+				logger.Warning("No value for custom field %s - leaving it unselected", field.label())
+				continue
+			}
+			if field.fieldType() == customFieldRadio {
+				err = selectRadio(page, field.Selector, value)
+			} else {
+				err = fillSelect(page, field.Selector, value, strings.ToLower(field.Match))
+			}
+		default:
+			if value == "" {
+				logger.Warning("No value for custom field %s - leaving it blank", field.label())
+				continue
+			}
+			err = fillField(ctx, locator, value)
+		}
+		if err != nil {
+			return fmt.Sprintf("Failed to fill %s: %v", field.label(), err), errorCategory(err.Error())
+		}
+		logger.Debug("Filled custom field %s", field.label())
+		pauseAfterField(page)
+	}
+	return "", CategoryNone
+}
+
+// fillSelect picks value in the <select> matched by selector, comparing it
+// with each option's value, its visible label, or (match "") either
+func fillSelect(page playwright.Page, selector, value, match string) error {
+	values := &[]string{value}
+	var options playwright.SelectOptionValues
+	switch match {
+	case selectByValue:
+		options.Values = values
+	case selectByLabel:
+		options.Labels = values
+	default:
+		options.ValuesOrLabels = values
+	}
+
+	selected, err := page.Locator(selector).SelectOption(options)
+	if err != nil {
+		return err
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("no option %q", value)
+	}
+	return nil
+}
+
+// radioLabel returns the text of a radio button's <label>
+const radioLabel = `el => (el.labels && el.labels.length ? el.labels[0].innerText : "")`
+
+// selectRadio checks the radio button in the group matched by groupSelector
+// whose value attribute or label equals value, ignoring case
+func selectRadio(page playwright.Page, groupSelector, value string) error {
+	radios := page.Locator(groupSelector)
+	count, err := radios.Count()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < count; i++ {
+		radio := radios.Nth(i)
+		if v, err := radio.GetAttribute("value"); err == nil && strings.EqualFold(strings.TrimSpace(v), value) {
+			return radio.Check()
+		}
+	}
+	for i := 0; i < count; i++ {
+		radio := radios.Nth(i)
+		label, err := radio.Evaluate(radioLabel, nil)
+		if text, ok := label.(string); err == nil && ok && strings.EqualFold(strings.TrimSpace(text), value) {
+			return radio.Check()
+		}
+	}
+	return fmt.Errorf("no option %q among %d radio buttons", value, count)
+}
+
+// pauseAfterField waits --field-delay, or a random --humanize-delay with --humanize
+func pauseAfterField(page playwright.Page) {
+	delay := config.FieldDelay
+	if config.Humanize {
+		delay = config.HumanizeDelay.Random()
+	}
+	page.WaitForTimeout(float64(delay.Milliseconds()))
+}
+
+// Fixed timeouts for quick checks that aren't worth a flag
+const (
+	probeTimeout       = time.Second      // Looking for an optional element that is either there or not
+	proxyVerifyTimeout = 10 * time.Second // Fetching the exit IP through a new proxy
+)
+
+// milliseconds converts d to the float milliseconds Playwright options take
+func milliseconds(d time.Duration) *float64 {
+	return playwright.Float(float64(d.Milliseconds()))
+}
+
 // contains checks if string contains substring (case-insensitive)
 func contains(s, substr string) bool {
-	// Simple case-insensitive check
-	return len(s) >= len(substr) && (s == substr ||
-		(len(s) > 0 && len(substr) > 0 &&
-			fmt.Sprintf("%v", s) != fmt.Sprintf("%v", substr)))
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
 }
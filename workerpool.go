@@ -0,0 +1,127 @@
+package main
+
+import "sync"
+
+// maxCampaignWorkers is the most workers one campaign may run
+const maxCampaignWorkers = 200
+
+// workerPool runs a campaign's workers and lets their number change mid-run.
+// Growing starts more workers on the same job queue; shrinking hands out
+// retire tokens, which idle workers take at once and busy ones after their
+// current job.
+type workerPool struct {
+	run      func(workerID int, retire <-chan struct{})
+	retire   chan struct{} // One token per worker that should exit
+	target   int           // Workers wanted once pending retirements are done
+	nextID   int
+	workers  []Worker // Workers not yet retired, closed when the campaign ends
+	finished bool     // The job queue is exhausted, so no more workers start
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+}
+
+// newWorkerPool returns a pool whose workers execute run until it returns
+func newWorkerPool(run func(workerID int, retire <-chan struct{})) *workerPool {
+	return &workerPool{run: run, retire: make(chan struct{}, maxCampaignWorkers)}
+}
+
+// Start launches the first n workers
+func (p *workerPool) Start(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := 0; i < n; i++ {
+		p.spawnLocked()
+	}
+	p.target = n
+}
+
+// Resize grows or shrinks the pool to n workers and returns the previous
+// size. It reports false once the job queue is exhausted.
+func (p *workerPool) Resize(n int) (int, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.finished {
+		return p.target, false
+	}
+
+	previous := p.target
+	diff := n - p.target
+	// Taking back retire tokens keeps workers that haven't left yet
+	for diff > 0 && p.cancelRetireLocked() {
+		diff--
+	}
+	for ; diff > 0; diff-- {
+		p.spawnLocked()
+	}
+	for ; diff < 0; diff++ {
+		p.retire <- struct{}{}
+	}
+	p.target = n
+	return previous, true
+}
+
+// cancelRetireLocked takes back one retire token no worker has claimed yet
+func (p *workerPool) cancelRetireLocked() bool {
+	select {
+	case <-p.retire:
+		return true
+	default:
+		return false
+	}
+}
+
+// spawnLocked starts one worker. Caller must hold p.mu.
+func (p *workerPool) spawnLocked() {
+	workerID := p.nextID
+	p.nextID++
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.run(workerID, p.retire)
+	}()
+}
+
+// Size is the number of workers the pool is running or settling to
+func (p *workerPool) Size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.target
+}
+
+// Add records a worker so Wait can close it
+func (p *workerPool) Add(worker Worker) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.workers = append(p.workers, worker)
+}
+
+// Retire closes a worker that took a retire token, so its browser and
+// browser slot are freed now rather than when the campaign ends
+func (p *workerPool) Retire(worker Worker) {
+	p.mu.Lock()
+	for i, w := range p.workers {
+		if w == worker {
+			p.workers = append(p.workers[:i], p.workers[i+1:]...)
+			break
+		}
+	}
+	p.mu.Unlock()
+	worker.Close()
+}
+
+// Finish marks the job queue exhausted; workers call it when jobs closes
+func (p *workerPool) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.finished = true
+}
+
+// Wait blocks until every worker has exited, then closes those not retired
+func (p *workerPool) Wait() {
+	p.wg.Wait()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, worker := range p.workers {
+		worker.Close()
+	}
+}